@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/slavtov/clean-architecture/pkg/store/postgres"
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply or revert the embedded database migrations",
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "up",
+			Short: "Apply all pending migrations",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return c.migrate(func(m *postgres.Migrator) error {
+					return m.Up()
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "down [N]",
+			Short: "Revert the last N migrations (default 1)",
+			Args:  cobra.MaximumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				steps := 1
+				if len(args) > 0 {
+					n, err := strconv.Atoi(args[0])
+					if err != nil || n < 1 {
+						return fmt.Errorf("invalid number of steps: %s", args[0])
+					}
+
+					steps = n
+				}
+
+				return c.migrate(func(m *postgres.Migrator) error {
+					return m.Down(steps)
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "goto V",
+			Short: "Migrate up or down to version V",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				version, err := strconv.ParseUint(args[0], 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid version: %s", args[0])
+				}
+
+				return c.migrate(func(m *postgres.Migrator) error {
+					return m.Goto(uint(version))
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "force V",
+			Short: "Mark version V as applied without running any migration",
+			Long: "Mark version V as applied and clear the dirty flag without running\n" +
+				"any migration. Use it after fixing a failed migration by hand or to\n" +
+				"baseline a database whose schema already matches version V.",
+			Args: cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				version, err := strconv.ParseUint(args[0], 10, 64)
+				if err != nil || version < 1 {
+					return fmt.Errorf("invalid version: %s", args[0])
+				}
+
+				return c.migrate(func(m *postgres.Migrator) error {
+					return m.Force(uint(version))
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "status",
+			Short: "List migrations and whether they are applied",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return c.migrate(printStatus)
+			},
+		},
+	)
+
+	return cmd
+}
+
+func printStatus(m *postgres.Migrator) error {
+	version, dirty, err := m.Version()
+	if err != nil {
+		return err
+	}
+
+	migrations, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		state := "pending"
+		if migration.Applied {
+			state = "applied"
+		}
+
+		if dirty && migration.Version == version {
+			state = "dirty"
+		}
+
+		fmt.Printf("%06d  %-8s %s\n", migration.Version, state, migration.Name)
+	}
+
+	fmt.Printf("version: %d, dirty: %t\n", version, dirty)
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+func newOutboxCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "outbox",
+		Short: "Inspect and replay outbox events",
+	}
+
+	cmd.AddCommand(
+		newOutboxRelayCmd(c),
+		newOutboxDeadCmd(c),
+		newOutboxRequeueCmd(c),
+	)
+
+	return cmd
+}
+
+func newOutboxRelayCmd(c *cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "relay",
+		Short: "Publish the pending events that are due once",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			a, close, err := c.openApp()
+			if err != nil {
+				return err
+			}
+			defer close()
+
+			total := 0
+			for {
+				n, err := a.Outbox.Relay(c.cfg.Outbox.BatchSize)
+				if err != nil {
+					return err
+				}
+
+				total += n
+				if n < c.cfg.Outbox.BatchSize {
+					break
+				}
+			}
+
+			fmt.Printf("published %d events\n", total)
+
+			return nil
+		},
+	}
+}
+
+func newOutboxDeadCmd(c *cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "dead",
+		Short: "List the events that ran out of attempts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			a, close, err := c.openApp()
+			if err != nil {
+				return err
+			}
+			defer close()
+
+			events, err := a.Outbox.GetDead()
+			if err != nil {
+				return err
+			}
+
+			for _, e := range events {
+				reason := ""
+				if e.LastError != nil {
+					reason = *e.LastError
+				}
+
+				fmt.Printf(
+					"%d\t%s\t%s\t%d attempts\t%s\n",
+					e.ID,
+					e.Type,
+					e.AggregateID,
+					e.Attempts,
+					reason,
+				)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newOutboxRequeueCmd(c *cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "requeue <id>",
+		Short: "Retry a dead event",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid event id: %s", args[0])
+			}
+
+			a, close, err := c.openApp()
+			if err != nil {
+				return err
+			}
+			defer close()
+
+			if err := a.Outbox.Requeue(id); err != nil {
+				return err
+			}
+
+			fmt.Printf("requeued event %d\n", id)
+
+			return nil
+		},
+	}
+}
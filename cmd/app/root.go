@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	schema "github.com/slavtov/clean-architecture/db"
+	"github.com/slavtov/clean-architecture/internal/app"
+	"github.com/slavtov/clean-architecture/internal/config"
+	"github.com/slavtov/clean-architecture/pkg/logger"
+	"github.com/slavtov/clean-architecture/pkg/store/postgres"
+	"github.com/slavtov/clean-architecture/pkg/store/redis"
+	"github.com/slavtov/clean-architecture/pkg/store/s3"
+	"github.com/spf13/cobra"
+)
+
+// cli is the state shared by every command: the flags of the root
+// command and what gets loaded from them.
+type cli struct {
+	configPath string
+	configName string
+	cfg        *config.Config
+	log        logger.Logger
+}
+
+func newRootCmd() *cobra.Command {
+	c := new(cli)
+
+	root := &cobra.Command{
+		Use:          "app",
+		Short:        "The Clean Architecture REST API",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return c.loadConfig()
+		},
+		// Without a subcommand the binary keeps starting the server.
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.serve()
+		},
+	}
+
+	root.PersistentFlags().StringVar(
+		&c.configPath,
+		"config-path",
+		getEnv("CONFIG_PATH", "./configs"),
+		"directory of the config file",
+	)
+	root.PersistentFlags().StringVar(
+		&c.configName,
+		"config-name",
+		getEnv("CONFIG_NAME", "config.local"),
+		"name of the config file without the extension",
+	)
+
+	root.AddCommand(
+		newServeCmd(c),
+		newMigrateCmd(c),
+		newSeedCmd(c),
+		newUserCmd(c),
+		newOutboxCmd(c),
+		newConfigCmd(c),
+	)
+
+	return root
+}
+
+func (c *cli) loadConfig() error {
+	cfg, err := config.LoadConfig(c.configPath, c.configName)
+	if err != nil {
+		return fmt.Errorf("fatal error config file: %v", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	c.cfg = cfg
+	c.log = logger.New()
+	c.log.Init(cfg.Server.Debug, cfg.Logger.Level)
+
+	return nil
+}
+
+func (c *cli) postgresConfig() *postgres.Config {
+	return postgres.NewConfig(
+		c.cfg.DB.Driver,
+		c.cfg.DB.Host,
+		c.cfg.DB.Port,
+		c.cfg.DB.User,
+		c.cfg.DB.Password,
+		c.cfg.DB.Name,
+		c.cfg.DB.SSL,
+	)
+}
+
+// openApp connects to every store and wires the use cases the same way
+// for the server and the commands. close releases the connections.
+func (c *cli) openApp() (a *app.App, close func(), err error) {
+	var closers []func() error
+
+	close = func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+	}
+
+	db, err := postgres.NewClient(c.postgresConfig())
+	if err != nil {
+		return nil, nil, fmt.Errorf("no db connection: %v", err)
+	}
+	closers = append(closers, db.Close)
+
+	if c.cfg.DB.AutoMigrate {
+		if err := c.migrate(func(m *postgres.Migrator) error {
+			return m.Up()
+		}); err != nil {
+			close()
+			return nil, nil, fmt.Errorf("migrate: %v", err)
+		}
+	}
+
+	rdb := redis.New(&redis.Config{
+		Addr:     c.cfg.Redis.Addr,
+		Password: c.cfg.Redis.Password,
+		DB:       c.cfg.Redis.DB,
+	}, c.log)
+	if err := rdb.Open(); err != nil {
+		close()
+		return nil, nil, fmt.Errorf("no redis connection: %v", err)
+	}
+	closers = append(closers, rdb.Close)
+
+	var blob s3.Store
+	if c.cfg.Blob.Driver == "s3" {
+		blob = s3.New(&s3.Config{
+			Endpoint:  c.cfg.Blob.S3.Endpoint,
+			AccessKey: c.cfg.Blob.S3.AccessKey,
+			SecretKey: c.cfg.Blob.S3.SecretKey,
+			Bucket:    c.cfg.Blob.S3.Bucket,
+			Region:    c.cfg.Blob.S3.Region,
+			SSL:       c.cfg.Blob.S3.SSL,
+		}, c.log)
+		if err := blob.Open(); err != nil {
+			close()
+			return nil, nil, fmt.Errorf("no s3 connection: %v", err)
+		}
+		closers = append(closers, blob.Close)
+	}
+
+	a, err = app.New(c.cfg, db, rdb, blob, c.log)
+	if err != nil {
+		close()
+		return nil, nil, err
+	}
+
+	return a, close, nil
+}
+
+// migrate runs fn with a migrator for the embedded migrations.
+func (c *cli) migrate(fn func(m *postgres.Migrator) error) error {
+	m, err := postgres.NewMigrator(
+		c.postgresConfig(),
+		schema.Migrations,
+		"migrations",
+	)
+	if err != nil {
+		return fmt.Errorf("no db connection: %v", err)
+	}
+	defer m.Close()
+
+	return fn(m)
+}
+
+func getEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return fallback
+}
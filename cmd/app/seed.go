@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	seedWords = strings.Fields(`lorem ipsum dolor sit amet consectetur
+		adipiscing elit sed do eiusmod tempor incididunt ut labore et dolore
+		magna aliqua enim ad minim veniam quis nostrud exercitation ullamco
+		laboris nisi aliquip ex ea commodo consequat`)
+	seedTags = []string{"golang", "postgres", "redis", "docker", "api", "testing"}
+)
+
+func newSeedCmd(c *cli) *cobra.Command {
+	var (
+		users    int
+		articles int
+		password string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Fill the database with fake users and articles",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			a, close, err := c.openApp()
+			if err != nil {
+				return err
+			}
+			defer close()
+
+			r := rand.New(rand.NewSource(time.Now().UnixNano()))
+			run := r.Int63()
+
+			for i := 0; i < users; i++ {
+				displayName, bio := strings.Title(sentence(r, 2)), sentence(r, 12)
+
+				user, err := a.Users.Create(&models.User{
+					Email:       fmt.Sprintf("seed%d.%x@example.com", i, run),
+					Password:    password,
+					DisplayName: &displayName,
+					Bio:         &bio,
+				})
+				if err != nil {
+					return err
+				}
+
+				for j := 0; j < articles; j++ {
+					if _, err := a.Articles.Store(&models.Article{
+						AuthorID: user.ID,
+						Title:    strings.Title(sentence(r, 3+r.Intn(5))),
+						Desc:     paragraph(r, 3+r.Intn(5)),
+						Status:   models.ArticlePublished,
+						Tags:     pick(r, seedTags, r.Intn(3)),
+					}); err != nil {
+						return err
+					}
+				}
+
+				fmt.Printf("created user %s (%s)\n", user.ID, user.Email)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&users, "users", 10, "number of users")
+	cmd.Flags().IntVar(&articles, "articles", 3, "number of articles per user")
+	cmd.Flags().StringVar(&password, "password", "seed-password-1", "password of every user")
+
+	return cmd
+}
+
+func sentence(r *rand.Rand, n int) string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = seedWords[r.Intn(len(seedWords))]
+	}
+
+	return strings.Join(words, " ")
+}
+
+func paragraph(r *rand.Rand, n int) string {
+	sentences := make([]string, n)
+	for i := range sentences {
+		s := sentence(r, 6+r.Intn(10))
+		sentences[i] = strings.ToUpper(s[:1]) + s[1:] + "."
+	}
+
+	return strings.Join(sentences, " ")
+}
+
+func pick(r *rand.Rand, from []string, n int) []string {
+	picked := make([]string, 0, n)
+	for _, i := range r.Perm(len(from))[:n] {
+		picked = append(picked, from[i])
+	}
+
+	return picked
+}
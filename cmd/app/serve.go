@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/slavtov/clean-architecture/internal/server"
+	"github.com/spf13/cobra"
+)
+
+func newServeCmd(c *cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Start the HTTP and gRPC servers",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.serve()
+		},
+	}
+}
+
+func (c *cli) serve() error {
+	fmt.Printf("PID: %d\n", os.Getpid())
+
+	a, close, err := c.openApp()
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	s := server.New(c.cfg, a, c.log)
+	if err := s.Run(); err != nil {
+		return fmt.Errorf("this server is not running: %v", err)
+	}
+
+	return nil
+}
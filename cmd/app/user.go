@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/spf13/cobra"
+)
+
+func newUserCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage users",
+	}
+
+	cmd.AddCommand(
+		newUserCreateCmd(c),
+		newUserRevokeSessionsCmd(c),
+	)
+
+	return cmd
+}
+
+func newUserCreateCmd(c *cli) *cobra.Command {
+	var (
+		user  models.User
+		admin bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a user",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			a, close, err := c.openApp()
+			if err != nil {
+				return err
+			}
+			defer close()
+
+			res, err := a.Users.Create(&user)
+			if err != nil {
+				return err
+			}
+
+			if admin {
+				if res, err = a.Users.SetRole(res.ID, models.RoleAdmin); err != nil {
+					return err
+				}
+			}
+
+			fmt.Printf("created %s user %s (%s)\n", res.Role, res.ID, res.Email)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&user.Email, "email", "", "email address")
+	cmd.Flags().StringVar(&user.Password, "password", "", "password")
+	cmd.Flags().StringVar(&user.Handle, "handle", "", "handle, generated when empty")
+	cmd.Flags().BoolVar(&admin, "admin", false, "grant the admin role")
+	cmd.MarkFlagRequired("email")
+	cmd.MarkFlagRequired("password")
+
+	return cmd
+}
+
+func newUserRevokeSessionsCmd(c *cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke-sessions <id>",
+		Short: "Log a user out of every session",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid user id: %s", args[0])
+			}
+
+			a, close, err := c.openApp()
+			if err != nil {
+				return err
+			}
+			defer close()
+
+			if _, err := a.Users.GetByID(id); err != nil {
+				return err
+			}
+
+			if err := a.Users.LogoutAll(id); err != nil {
+				return err
+			}
+
+			fmt.Printf("revoked the sessions of %s\n", id)
+
+			return nil
+		},
+	}
+}
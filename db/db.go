@@ -0,0 +1,9 @@
+package db
+
+import "embed"
+
+// Migrations holds the schema migrations, so that the binary can apply
+// them without the migrations directory on disk.
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
@@ -0,0 +1,12 @@
+CREATE TABLE article_revisions (
+    article_id  uuid NOT NULL REFERENCES articles (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    rev         integer NOT NULL CHECK (rev > 0),
+    editor_id   uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    title       varchar(250) NOT NULL CHECK (title <> ''),
+    "desc"      text NOT NULL CHECK ("desc" <> ''),
+    created_at  timestamp with time zone NOT NULL DEFAULT current_timestamp,
+    PRIMARY KEY (article_id, rev)
+);
+
+INSERT INTO article_revisions (article_id, rev, editor_id, title, "desc", created_at)
+    SELECT id, 1, author_id, title, "desc", updated_at FROM articles;
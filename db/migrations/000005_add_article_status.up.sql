@@ -0,0 +1,7 @@
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS status varchar(20) NOT NULL DEFAULT 'draft' 
+    CHECK (status IN ('draft', 'scheduled', 'published', 'archived'));
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS published_at timestamp with time zone;
+
+UPDATE articles SET status = 'published', published_at = created_at;
+
+CREATE INDEX IF NOT EXISTS articles_scheduled_idx ON articles (published_at) WHERE status = 'scheduled';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS article_tags CASCADE;
+DROP TABLE IF EXISTS tags CASCADE;
@@ -0,0 +1,16 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+
+CREATE TABLE tags (
+    id          uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name        varchar(50) UNIQUE NOT NULL CHECK (name <> ''),
+    created_at  timestamp with time zone NOT NULL DEFAULT current_timestamp
+);
+
+CREATE TABLE article_tags (
+    article_id  uuid NOT NULL REFERENCES articles (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    tag_id      uuid NOT NULL REFERENCES tags (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    PRIMARY KEY (article_id, tag_id)
+);
+
+CREATE INDEX article_tags_tag_id_idx ON article_tags (tag_id);
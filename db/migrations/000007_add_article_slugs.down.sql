@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS article_slugs CASCADE;
+
+ALTER TABLE articles DROP CONSTRAINT IF EXISTS articles_slug_key;
+ALTER TABLE articles DROP COLUMN IF EXISTS slug;
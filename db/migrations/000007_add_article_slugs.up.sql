@@ -0,0 +1,20 @@
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS slug varchar(300);
+
+UPDATE articles SET slug = COALESCE(
+    NULLIF(trim(BOTH '-' FROM lower(regexp_replace(title, '[^[:alnum:]]+', '-', 'g'))), '') || '-',
+    ''
+) || left(id::text, 8);
+
+ALTER TABLE articles ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE articles ADD CONSTRAINT articles_slug_key UNIQUE (slug);
+
+CREATE TABLE article_slugs (
+    slug        varchar(300) PRIMARY KEY CHECK (slug <> ''),
+    article_id  uuid NOT NULL REFERENCES articles (id) ON DELETE CASCADE ON UPDATE CASCADE 
+                DEFERRABLE INITIALLY DEFERRED,
+    created_at  timestamp with time zone NOT NULL DEFAULT current_timestamp
+);
+
+CREATE INDEX article_slugs_article_id_idx ON article_slugs (article_id);
+
+INSERT INTO article_slugs (slug, article_id) SELECT slug, id FROM articles;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS comments CASCADE;
+
+ALTER TABLE users DROP COLUMN IF EXISTS "role";
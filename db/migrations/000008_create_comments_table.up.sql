@@ -0,0 +1,25 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS "role" varchar(20) NOT NULL DEFAULT 'user' 
+    CHECK ("role" IN ('user', 'moderator', 'admin'));
+
+CREATE TABLE comments (
+    id          uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    article_id  uuid NOT NULL REFERENCES articles (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    author_id   uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    parent_id   uuid REFERENCES comments (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    root_id     uuid NOT NULL,
+    depth       integer NOT NULL DEFAULT 0 CHECK (depth >= 0),
+    body        text NOT NULL CHECK (body <> ''),
+    hidden_at   timestamp with time zone,
+    hidden_by   uuid REFERENCES users (id) ON DELETE SET NULL ON UPDATE CASCADE,
+    updated_at  timestamp with time zone NOT NULL DEFAULT current_timestamp,
+    created_at  timestamp with time zone NOT NULL DEFAULT current_timestamp,
+    deleted_at  timestamp with time zone
+);
+
+CREATE INDEX comments_article_id_idx ON comments (article_id, created_at) WHERE parent_id IS NULL;
+CREATE INDEX comments_root_id_idx ON comments (root_id);
+CREATE INDEX comments_parent_id_idx ON comments (parent_id);
+CREATE INDEX comments_deleted_at_idx ON comments (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS article_bookmarks CASCADE;
+DROP TABLE IF EXISTS article_likes CASCADE;
+
+ALTER TABLE articles DROP COLUMN IF EXISTS likes_count;
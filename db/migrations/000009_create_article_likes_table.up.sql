@@ -0,0 +1,18 @@
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS likes_count integer NOT NULL DEFAULT 0 CHECK (likes_count >= 0);
+
+CREATE TABLE article_likes (
+    article_id  uuid NOT NULL REFERENCES articles (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    user_id     uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    created_at  timestamp with time zone NOT NULL DEFAULT current_timestamp,
+    PRIMARY KEY (article_id, user_id)
+);
+
+CREATE TABLE article_bookmarks (
+    article_id  uuid NOT NULL REFERENCES articles (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    user_id     uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    created_at  timestamp with time zone NOT NULL DEFAULT current_timestamp,
+    PRIMARY KEY (article_id, user_id)
+);
+
+CREATE INDEX article_likes_user_id_idx ON article_likes (user_id);
+CREATE INDEX article_bookmarks_user_id_idx ON article_bookmarks (user_id, created_at);
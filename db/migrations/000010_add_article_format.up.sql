@@ -0,0 +1,2 @@
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS format varchar(20) NOT NULL DEFAULT 'plain' 
+    CHECK (format IN ('plain', 'markdown'));
@@ -0,0 +1,18 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+
+CREATE TABLE attachments (
+    id              uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    article_id      uuid NOT NULL REFERENCES articles (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    uploader_id     uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    key             varchar(250) UNIQUE NOT NULL CHECK (key <> ''),
+    filename        varchar(255) NOT NULL CHECK (filename <> ''),
+    content_type    varchar(100) NOT NULL CHECK (content_type <> ''),
+    size            bigint NOT NULL CHECK (size >= 0),
+    width           integer,
+    height          integer,
+    checksum        varchar(64) NOT NULL,
+    created_at      timestamp with time zone NOT NULL DEFAULT current_timestamp
+);
+
+CREATE INDEX attachments_article_id_idx ON attachments (article_id, created_at);
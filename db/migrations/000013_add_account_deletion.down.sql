@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS audit_log;
+
+DROP INDEX IF EXISTS users_erase_at_idx;
+ALTER TABLE users DROP COLUMN IF EXISTS erase_at;
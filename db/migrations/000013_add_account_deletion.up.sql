@@ -0,0 +1,17 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS erase_at timestamp with time zone;
+
+-- Accounts deleted before this migration get their erase_at from the purge
+-- job, which knows the configured account.deletion_grace_period.
+
+CREATE INDEX IF NOT EXISTS users_erase_at_idx ON users (erase_at) WHERE erase_at IS NOT NULL;
+
+CREATE TABLE audit_log (
+    id          bigserial PRIMARY KEY,
+    user_id     uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    action      varchar(50) NOT NULL CHECK (action <> ''),
+    ip          varchar(45) NOT NULL DEFAULT '',
+    user_agent  varchar(500) NOT NULL DEFAULT '',
+    created_at  timestamp with time zone NOT NULL DEFAULT current_timestamp
+);
+
+CREATE INDEX audit_log_user_id_idx ON audit_log (user_id, created_at);
@@ -0,0 +1,15 @@
+CREATE TABLE outbox (
+    id            bigserial PRIMARY KEY,
+    type          varchar(50) NOT NULL CHECK (type <> ''),
+    aggregate_id  uuid NOT NULL,
+    payload       jsonb NOT NULL,
+    status        varchar(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'published', 'dead')),
+    attempts      integer NOT NULL DEFAULT 0,
+    last_error    text,
+    available_at  timestamp with time zone NOT NULL DEFAULT current_timestamp,
+    created_at    timestamp with time zone NOT NULL DEFAULT current_timestamp,
+    published_at  timestamp with time zone
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (available_at, id) WHERE status = 'pending';
+CREATE INDEX outbox_published_at_idx ON outbox (published_at) WHERE status = 'published';
@@ -0,0 +1,33 @@
+CREATE TABLE webhooks (
+    id          uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id     uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    url         varchar(500) NOT NULL CHECK (url <> ''),
+    events      text[] NOT NULL CHECK (cardinality(events) > 0),
+    secret      varchar(64) NOT NULL,
+    active      boolean NOT NULL DEFAULT true,
+    created_at  timestamp with time zone NOT NULL DEFAULT current_timestamp,
+    updated_at  timestamp with time zone NOT NULL DEFAULT current_timestamp
+);
+
+CREATE INDEX webhooks_user_id_idx ON webhooks (user_id);
+
+CREATE TABLE webhook_deliveries (
+    id               uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    webhook_id       uuid NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    event_id         bigint NOT NULL,
+    event_type       varchar(50) NOT NULL,
+    payload          jsonb NOT NULL,
+    status           varchar(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts         integer NOT NULL DEFAULT 0,
+    next_attempt_at  timestamp with time zone NOT NULL DEFAULT current_timestamp,
+    response_status  integer,
+    response_body    text,
+    error            text,
+    duration_ms      integer,
+    created_at       timestamp with time zone NOT NULL DEFAULT current_timestamp,
+    delivered_at     timestamp with time zone,
+    UNIQUE (webhook_id, event_id)
+);
+
+CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX webhook_deliveries_webhook_id_idx ON webhook_deliveries (webhook_id, created_at);
@@ -0,0 +1,189 @@
+package app
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	articleRepository "github.com/slavtov/clean-architecture/internal/article/repository"
+	articleUseCase "github.com/slavtov/clean-architecture/internal/article/usecase"
+	attachmentRepository "github.com/slavtov/clean-architecture/internal/attachment/repository"
+	attachmentUseCase "github.com/slavtov/clean-architecture/internal/attachment/usecase"
+	authRepository "github.com/slavtov/clean-architecture/internal/auth/repository"
+	authUseCase "github.com/slavtov/clean-architecture/internal/auth/usecase"
+	commentRepository "github.com/slavtov/clean-architecture/internal/comment/repository"
+	commentUseCase "github.com/slavtov/clean-architecture/internal/comment/usecase"
+	"github.com/slavtov/clean-architecture/internal/config"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/internal/domain/usecases"
+	feedRepository "github.com/slavtov/clean-architecture/internal/feed/repository"
+	feedUseCase "github.com/slavtov/clean-architecture/internal/feed/usecase"
+	outboxRepository "github.com/slavtov/clean-architecture/internal/outbox/repository"
+	outboxUseCase "github.com/slavtov/clean-architecture/internal/outbox/usecase"
+	"github.com/slavtov/clean-architecture/internal/uow"
+	webhookRepository "github.com/slavtov/clean-architecture/internal/webhook/repository"
+	webhookUseCase "github.com/slavtov/clean-architecture/internal/webhook/usecase"
+	"github.com/slavtov/clean-architecture/pkg/logger"
+	"github.com/slavtov/clean-architecture/pkg/mailer"
+	"github.com/slavtov/clean-architecture/pkg/password"
+	"github.com/slavtov/clean-architecture/pkg/store/redis"
+	"github.com/slavtov/clean-architecture/pkg/store/s3"
+	"github.com/slavtov/clean-architecture/pkg/webhook"
+)
+
+// App holds the use cases wired to their repositories. The HTTP server
+// and the CLI commands share it, so they always run the same code.
+type App struct {
+	Users       usecases.UserUseCase
+	Articles    usecases.ArticleUseCase
+	Comments    usecases.CommentUseCase
+	Attachments usecases.AttachmentUseCase
+	Outbox      usecases.OutboxUseCase
+	Webhooks    usecases.WebhookUseCase
+	Feed        usecases.FeedUseCase
+}
+
+func New(
+	cfg *config.Config,
+	db *sqlx.DB,
+	rdb redis.Store,
+	blob s3.Store,
+	log logger.Logger,
+) (*App, error) {
+	hasher, err := password.NewHasher(&password.Config{
+		Algorithm:   cfg.Password.Algorithm,
+		BcryptCost:  cfg.Password.BcryptCost,
+		Memory:      cfg.Password.Argon2.Memory,
+		Iterations:  cfg.Password.Argon2.Iterations,
+		Parallelism: cfg.Password.Argon2.Parallelism,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	authRepo := authRepository.NewPGRepository(db)
+	authRedisRepo := authRepository.NewRedisRepository(rdb)
+	articleRepo := articleRepository.NewPGRepository(db)
+	articleRedisRepo := articleRepository.NewRedisRepository(rdb)
+	commentRepo := commentRepository.NewPGRepository(db)
+	commentRedisRepo := commentRepository.NewRedisRepository(rdb)
+	attachmentRepo := attachmentRepository.NewPGRepository(db)
+	outboxRepo := outboxRepository.NewPGRepository(db)
+	webhookRepo := webhookRepository.NewPGRepository(db)
+	feedRedisRepo := feedRepository.NewRedisRepository(rdb, cfg.Feed.MaxLen)
+	unitOfWork := uow.New(db)
+
+	articleUC := articleUseCase.New(
+		articleRepo,
+		articleRedisRepo,
+		unitOfWork,
+		log,
+	)
+	blobStore := newBlobStore(cfg, blob)
+
+	authUC := authUseCase.New(
+		cfg,
+		authRepo,
+		authRedisRepo,
+		newMailer(cfg, log),
+		blobStore,
+		unitOfWork,
+		articleUC,
+		hasher,
+		log,
+	)
+	attachmentUC := attachmentUseCase.New(
+		cfg,
+		attachmentRepo,
+		blobStore,
+		articleUC,
+		log,
+	)
+	commentUC := commentUseCase.New(
+		cfg,
+		commentRepo,
+		commentRedisRepo,
+		articleUC,
+		authUC,
+		log,
+	)
+
+	webhookUC := webhookUseCase.New(
+		cfg,
+		webhookRepo,
+		log,
+	)
+	feedUC := feedUseCase.New(
+		cfg,
+		feedRedisRepo,
+		articleUC,
+		log,
+	)
+	outboxUC := outboxUseCase.New(
+		cfg,
+		outboxRepo,
+		outboxRepository.NewMultiSink(
+			newEventSink(cfg, rdb, log),
+			webhookUC,
+			feedUC,
+		),
+		log,
+	)
+
+	return &App{
+		Users:       authUC,
+		Articles:    articleUC,
+		Comments:    commentUC,
+		Attachments: attachmentUC,
+		Outbox:      outboxUC,
+		Webhooks:    webhookUC,
+		Feed:        feedUC,
+	}, nil
+}
+
+func newBlobStore(cfg *config.Config, blob s3.Store) repositories.BlobStore {
+	if cfg.Blob.Driver == "s3" {
+		return attachmentRepository.NewS3Repository(blob)
+	}
+
+	return attachmentRepository.NewLocalRepository(cfg.Blob.Path)
+}
+
+func newMailer(cfg *config.Config, log logger.Logger) mailer.Mailer {
+	if cfg.Mailer.Driver == "smtp" {
+		return mailer.NewSMTP(&mailer.Config{
+			Host:     cfg.Mailer.Host,
+			Port:     cfg.Mailer.Port,
+			Username: cfg.Mailer.Username,
+			Password: cfg.Mailer.Password,
+			From:     cfg.Mailer.From,
+		})
+	}
+
+	return mailer.NewLog(log)
+}
+
+func newEventSink(
+	cfg *config.Config,
+	rdb redis.Store,
+	log logger.Logger,
+) repositories.EventSink {
+	switch cfg.Outbox.Sink {
+	case "redis":
+		return outboxRepository.NewRedisSink(
+			rdb,
+			cfg.Outbox.Redis.Stream,
+			cfg.Outbox.Redis.MaxLen,
+		)
+	case "webhook":
+		return outboxRepository.NewWebhookSink(
+			webhook.New(
+				time.Second*time.Duration(cfg.Outbox.Webhook.Timeout),
+				true,
+			),
+			cfg.Outbox.Webhook.URL,
+			cfg.Outbox.Webhook.Secret,
+		)
+	}
+
+	return outboxRepository.NewLogSink(log)
+}
@@ -0,0 +1,218 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/usecases"
+	apiv1 "github.com/slavtov/clean-architecture/pkg/api/v1"
+	"github.com/slavtov/clean-architecture/pkg/logger"
+	"github.com/slavtov/clean-architecture/pkg/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// OptionalAuthMethods may be called anonymously.
+var OptionalAuthMethods = []string{
+	"/api.v1.ArticleService/ListArticles",
+	"/api.v1.ArticleService/GetArticle",
+}
+
+type handler struct {
+	apiv1.UnimplementedArticleServiceServer
+	articleUseCase usecases.ArticleUseCase
+	log            logger.Logger
+}
+
+func newHandler(
+	au usecases.ArticleUseCase,
+	log logger.Logger,
+) *handler {
+	return &handler{
+		articleUseCase: au,
+		log:            log,
+	}
+}
+
+func Init(
+	s grpc.ServiceRegistrar,
+	au usecases.ArticleUseCase,
+	log logger.Logger,
+) {
+	apiv1.RegisterArticleServiceServer(s, newHandler(au, log))
+}
+
+func (h *handler) ListArticles(
+	ctx context.Context,
+	req *apiv1.ListArticlesRequest,
+) (*apiv1.ListArticlesResponse, error) {
+	filter := &models.ArticleFilter{
+		ViewerID: utils.GetGrpcViewerID(ctx),
+		Tags:     req.GetTags(),
+	}
+
+	if req.GetAuthorId() != "" {
+		authorID, err := uuid.Parse(req.GetAuthorId())
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid author_id")
+		}
+
+		filter.AuthorID = &authorID
+	}
+
+	res, err := h.articleUseCase.GetAll(filter)
+	if err != nil {
+		h.log.Errorf("article.UseCase.GetAll: %v", err)
+		return nil, err
+	}
+
+	articles := make([]*apiv1.Article, 0, len(res))
+	for i := range res {
+		articles = append(articles, articleResponse(&res[i]))
+	}
+
+	return &apiv1.ListArticlesResponse{
+		TotalCount: int32(len(articles)),
+		Articles:   articles,
+	}, nil
+}
+
+func (h *handler) GetArticle(
+	ctx context.Context,
+	req *apiv1.GetArticleRequest,
+) (*apiv1.Article, error) {
+	viewerID := utils.GetGrpcViewerID(ctx)
+
+	if id, err := uuid.Parse(req.GetId()); err == nil {
+		article, err := h.articleUseCase.GetByID(id, viewerID)
+		if err != nil {
+			h.log.Errorf("article.UseCase.GetByID: %v", err)
+			return nil, err
+		}
+
+		return articleResponse(&article), nil
+	}
+
+	article, err := h.articleUseCase.GetBySlug(req.GetId(), viewerID)
+	if err != nil {
+		h.log.Errorf("article.UseCase.GetBySlug: %v", err)
+		return nil, err
+	}
+
+	return articleResponse(&article), nil
+}
+
+func (h *handler) CreateArticle(
+	ctx context.Context,
+	req *apiv1.CreateArticleRequest,
+) (*apiv1.Article, error) {
+	createdArticle, err := h.articleUseCase.Store(&models.Article{
+		AuthorID:    utils.GetGrpcViewerID(ctx),
+		Title:       req.GetTitle(),
+		Desc:        req.GetDesc(),
+		Format:      models.ArticleFormat(req.GetFormat()),
+		Status:      models.ArticleStatus(req.GetStatus()),
+		PublishedAt: timePtr(req.GetPublishedAt()),
+		Tags:        req.GetTags(),
+	})
+	if err != nil {
+		h.log.Errorf("article.UseCase.Store: %v", err)
+		return nil, err
+	}
+
+	return articleResponse(createdArticle), nil
+}
+
+func (h *handler) UpdateArticle(
+	ctx context.Context,
+	req *apiv1.UpdateArticleRequest,
+) (*apiv1.Article, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, echo.ErrNotFound
+	}
+
+	a := &models.Article{
+		ID:          id,
+		AuthorID:    utils.GetGrpcViewerID(ctx),
+		Title:       req.GetTitle(),
+		Desc:        req.GetDesc(),
+		Format:      models.ArticleFormat(req.GetFormat()),
+		Status:      models.ArticleStatus(req.GetStatus()),
+		PublishedAt: timePtr(req.GetPublishedAt()),
+		Tags:        req.GetTags(),
+	}
+
+	if req.GetUpdatedAt() != nil {
+		if err := req.GetUpdatedAt().CheckValid(); err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid updated_at")
+		}
+
+		a.UpdatedAt = req.GetUpdatedAt().AsTime()
+	}
+
+	updatedArticle, err := h.articleUseCase.Update(a)
+	if err != nil {
+		h.log.Errorf("article.UseCase.Update: %v", err)
+		return nil, err
+	}
+
+	return articleResponse(updatedArticle), nil
+}
+
+func (h *handler) DeleteArticle(
+	ctx context.Context,
+	req *apiv1.DeleteArticleRequest,
+) (*emptypb.Empty, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, echo.ErrNotFound
+	}
+
+	if err := h.articleUseCase.Delete(models.Article{
+		ID:       id,
+		AuthorID: utils.GetGrpcViewerID(ctx),
+	}); err != nil {
+		h.log.Errorf("article.UseCase.Delete: %v", err)
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func articleResponse(a *models.Article) *apiv1.Article {
+	res := &apiv1.Article{
+		Id:         a.ID.String(),
+		AuthorId:   a.AuthorID.String(),
+		Title:      a.Title,
+		Slug:       a.Slug,
+		Desc:       a.Desc,
+		DescHtml:   a.DescHTML,
+		Format:     string(a.Format),
+		Status:     string(a.Status),
+		Tags:       a.Tags,
+		LikesCount: int32(a.LikesCount),
+		UpdatedAt:  timestamppb.New(a.UpdatedAt),
+		CreatedAt:  timestamppb.New(a.CreatedAt),
+	}
+
+	if a.PublishedAt != nil {
+		res.PublishedAt = timestamppb.New(*a.PublishedAt)
+	}
+
+	return res
+}
+
+func timePtr(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+
+	t := ts.AsTime()
+	return &t
+}
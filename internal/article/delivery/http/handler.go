@@ -2,11 +2,17 @@ package http
 
 import (
 	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/slavtov/clean-architecture/internal/config"
 	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
 	"github.com/slavtov/clean-architecture/internal/domain/usecases"
 	"github.com/slavtov/clean-architecture/internal/middleware"
 	"github.com/slavtov/clean-architecture/pkg/logger"
@@ -14,17 +20,20 @@ import (
 )
 
 type handler struct {
+	cfg            *config.Config
 	articleUseCase usecases.ArticleUseCase
 	userUseCase    usecases.UserUseCase
 	log            logger.Logger
 }
 
 func newHandler(
+	cfg *config.Config,
 	au usecases.ArticleUseCase,
 	uu usecases.UserUseCase,
 	log logger.Logger,
 ) *handler {
 	return &handler{
+		cfg:            cfg,
 		articleUseCase: au,
 		userUseCase:    uu,
 		log:            log,
@@ -38,31 +47,139 @@ func Init(
 	uu usecases.UserUseCase,
 	log logger.Logger,
 ) {
-	h := newHandler(au, uu, log)
+	h := newHandler(cfg, au, uu, log)
 	auth := middleware.Auth(cfg, uu, log)
+	optionalAuth := middleware.OptionalAuth(cfg, uu, log)
+	cacheControl := middleware.CacheControl(cfg)
 
-	e.GET("/articles", h.GetAll)
-	e.GET("/articles/:id", h.GetByID)
+	e.GET("/tags", h.GetTags, cacheControl)
+	e.GET("/articles", h.GetAll, optionalAuth, cacheControl)
+	e.GET("/articles/:id", h.GetByID, optionalAuth, cacheControl)
 	e.POST("/articles", h.Store, auth)
+	e.POST("/articles/bulk", h.StoreMany, auth)
+	e.PUT("/articles/bulk", h.UpdateMany, auth)
+	e.DELETE("/articles/bulk", h.DeleteMany, auth)
 	e.PUT("/articles/:id", h.Update, auth)
 	e.DELETE("/articles/:id", h.Delete, auth)
+	e.POST("/articles/:id/restore", h.Restore, auth)
+	e.GET("/articles/:id/revisions", h.GetRevisions, optionalAuth)
+	e.GET("/articles/:id/revisions/diff", h.DiffRevisions, optionalAuth)
+	e.GET("/articles/:id/revisions/:rev", h.GetRevision, optionalAuth)
+	e.POST("/articles/:id/revisions/:rev/restore", h.RestoreRevision, auth)
+	e.PUT("/articles/:id/like", h.Like, auth)
+	e.DELETE("/articles/:id/like", h.Unlike, auth)
+	e.PUT("/articles/:id/bookmark", h.Bookmark, auth)
+	e.DELETE("/articles/:id/bookmark", h.Unbookmark, auth)
+	e.GET("/bookmarks", h.GetBookmarks, auth)
+	e.GET("/users/:handle/articles", h.GetByAuthor, optionalAuth, cacheControl)
 }
 
 // GetAll godoc
 // @Tags Articles
 // @Summary Get all articles
+// @Description Returns published articles and, for an authenticated caller, their own unpublished ones.
+// @Description Authenticated callers also get their own liked and bookmarked state.
 // @Accept json
 // @Produce json
+// @Param tag query []string false "Only articles with any of these tags" collectionFormat(multi)
+// @Param include query string false "Embed related resources" Enums(author)
+// @Param If-None-Match header string false "ETag of the cached list"
+// @Param If-Modified-Since header string false "Last-Modified of the cached list"
 // @Success 200 {object} models.ArticlesList
-// @Failure 500 {object} swagger.Error
+// @Success 304
+// @Header 200,304 {string} ETag "List version"
+// @Header 200,304 {string} Last-Modified "Latest article update"
+// @Header 200,304 {string} Cache-Control "Caching policy"
+// @Failure 400,500 {object} swagger.Error
 // @Router /articles [get]
 func (h *handler) GetAll(c echo.Context) error {
-	res, err := h.articleUseCase.GetAll()
+	return h.getAll(c, &models.ArticleFilter{
+		ViewerID: utils.GetCtxViewerID(c),
+		Tags:     getTags(c),
+	})
+}
+
+// GetByAuthor godoc
+// @Tags Articles
+// @Summary Get articles of a user
+// @Description Returns the user's published articles and, for the user themselves, their unpublished ones.
+// @Accept json
+// @Produce json
+// @Param handle path string true "User handle or ID"
+// @Param tag query []string false "Only articles with any of these tags" collectionFormat(multi)
+// @Param include query string false "Embed related resources" Enums(author)
+// @Param If-None-Match header string false "ETag of the cached list"
+// @Param If-Modified-Since header string false "Last-Modified of the cached list"
+// @Success 200 {object} models.ArticlesList
+// @Success 304
+// @Header 200,304 {string} ETag "List version"
+// @Header 200,304 {string} Last-Modified "Latest article update"
+// @Header 200,304 {string} Cache-Control "Caching policy"
+// @Failure 400,404,500 {object} swagger.Error
+// @Router /users/{handle}/articles [get]
+func (h *handler) GetByAuthor(c echo.Context) error {
+	var (
+		user models.User
+		err  error
+	)
+
+	if id, parseErr := uuid.Parse(c.Param("handle")); parseErr == nil {
+		user, err = h.userUseCase.GetByID(id)
+	} else {
+		user, err = h.userUseCase.GetByHandle(c.Param("handle"))
+	}
+
+	if err != nil {
+		h.log.Errorf("auth.UseCase.GetByHandle: %v", err)
+		return err
+	}
+
+	return h.getAll(c, &models.ArticleFilter{
+		ViewerID: utils.GetCtxViewerID(c),
+		AuthorID: &user.ID,
+		Tags:     getTags(c),
+	})
+}
+
+func (h *handler) getAll(c echo.Context, filter *models.ArticleFilter) error {
+	include, err := includeAuthor(c)
+	if err != nil {
+		return err
+	}
+
+	res, err := h.articleUseCase.GetAll(filter)
 	if err != nil {
 		h.log.Errorf("article.UseCase.GetAll: %v", err)
 		return err
 	}
 
+	var authors map[uuid.UUID]models.User
+	if include {
+		if authors, err = h.setAuthors(res); err != nil {
+			return err
+		}
+	}
+
+	var lastModified time.Time
+	parts := make([]string, 0, len(res))
+	for _, a := range res {
+		parts = append(parts, getVersion(&a))
+		if a.UpdatedAt.After(lastModified) {
+			lastModified = a.UpdatedAt
+		}
+
+		if author, ok := authors[a.AuthorID]; ok {
+			parts = append(parts, utils.ETag(author.UpdatedAt))
+			if author.UpdatedAt.After(lastModified) {
+				lastModified = author.UpdatedAt
+			}
+		}
+	}
+
+	if utils.NotModified(c, utils.HashETag(parts...), lastModified) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
 	return c.JSON(http.StatusOK, &models.ArticlesList{
 		TotalCount: len(res),
 		Articles:   res,
@@ -71,23 +188,90 @@ func (h *handler) GetAll(c echo.Context) error {
 
 // GetByID godoc
 // @Tags Articles
-// @Summary Get article by ID
+// @Summary Get article by ID or slug
+// @Description Unpublished articles are only visible to their author.
+// @Description Previous slugs of an article redirect to the current one.
+// @Description Authenticated callers also get their own liked and bookmarked state.
 // @Accept json
 // @Produce json
-// @Param id path string true "Article ID"
+// @Param id path string true "Article ID or slug"
+// @Param include query string false "Embed related resources" Enums(author)
+// @Param If-None-Match header string false "ETag of the cached article"
+// @Param If-Modified-Since header string false "Last-Modified of the cached article"
 // @Success 200 {object} models.Article
+// @Success 301
+// @Success 304
+// @Header 301 {string} Location "Current article URL"
+// @Header 200,304 {string} ETag "Article version"
+// @Header 200,304 {string} Last-Modified "Article update time"
+// @Header 200,304 {string} Cache-Control "Caching policy"
 // @Failure 400,404,500 {object} swagger.Error
 // @Router /articles/{id} [get]
 func (h *handler) GetByID(c echo.Context) error {
-	id, err := uuid.Parse(c.Param("id"))
+	include, err := includeAuthor(c)
 	if err != nil {
-		return echo.ErrNotFound
+		return err
 	}
 
-	article, err := h.articleUseCase.GetByID(id)
-	if err != nil {
-		h.log.Errorf("article.UseCase.GetByID: %v", err)
-		return err
+	viewerID := utils.GetCtxViewerID(c)
+
+	var article models.Article
+
+	if id, err := uuid.Parse(c.Param("id")); err == nil {
+		if article, err = h.articleUseCase.GetByID(id, viewerID); err != nil {
+			h.log.Errorf("article.UseCase.GetByID: %v", err)
+			return err
+		}
+	} else {
+		slug, err := url.PathUnescape(c.Param("id"))
+		if err != nil {
+			return echo.ErrNotFound
+		}
+
+		if article, err = h.articleUseCase.GetBySlug(slug, viewerID); err != nil {
+			h.log.Errorf("article.UseCase.GetBySlug: %v", err)
+			return err
+		}
+
+		if article.Slug != slug {
+			location := path.Join(
+				path.Dir(c.Request().URL.Path),
+				url.PathEscape(article.Slug),
+			)
+			if query := c.Request().URL.RawQuery; query != "" {
+				location += "?" + query
+			}
+
+			return c.Redirect(http.StatusMovedPermanently, location)
+		}
+	}
+
+	parts := []string{getVersion(&article)}
+	lastModified := article.UpdatedAt
+
+	if include {
+		articles := []models.Article{article}
+
+		authors, err := h.setAuthors(articles)
+		if err != nil {
+			return err
+		}
+		article = articles[0]
+
+		if author, ok := authors[article.AuthorID]; ok {
+			parts = append(parts, utils.ETag(author.UpdatedAt))
+			if author.UpdatedAt.After(lastModified) {
+				lastModified = author.UpdatedAt
+			}
+		}
+	}
+
+	// Likes and bookmarks do not touch updated_at, so the tag covers them
+	// too; it still carries updated_at for If-Match.
+	etag := utils.VersionETag(article.UpdatedAt, parts...)
+
+	if utils.NotModified(c, etag, lastModified) {
+		return c.NoContent(http.StatusNotModified)
 	}
 
 	return c.JSON(http.StatusOK, article)
@@ -127,10 +311,12 @@ func (h *handler) Store(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param id path string true "Article ID"
+// @Param If-Match header string false "ETag of the article being edited"
 // @Param body body swagger.ArticleRequest true "Body"
 // @Security ApiKeyAuth
 // @Success 200 {object} models.Article
-// @Failure 400,401,404,500 {object} swagger.Error
+// @Header 200 {string} ETag "Article version"
+// @Failure 400,401,404,412,500 {object} swagger.Error
 // @Router /articles/{id} [put]
 func (h *handler) Update(c echo.Context) error {
 	id, err := uuid.Parse(c.Param("id"))
@@ -147,12 +333,22 @@ func (h *handler) Update(c echo.Context) error {
 	a.ID = id
 	a.AuthorID = utils.GetCtxID(c)
 
+	a.UpdatedAt, err = utils.GetIfMatch(c)
+	if err != nil {
+		return repositories.ErrArticleConflict
+	}
+
 	updatedArticle, err := h.articleUseCase.Update(a)
 	if err != nil {
 		h.log.Errorf("article.UseCase.Update: %v", err)
 		return err
 	}
 
+	c.Response().Header().Set(
+		utils.HeaderETag,
+		utils.ETag(updatedArticle.UpdatedAt),
+	)
+
 	return c.JSON(http.StatusOK, updatedArticle)
 }
 
@@ -182,3 +378,557 @@ func (h *handler) Delete(c echo.Context) error {
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+// StoreMany godoc
+// @Tags Articles
+// @Summary Add articles in bulk
+// @Description Creates up to articles.bulk_limit articles in one transaction. Invalid items are
+// @Description reported with their own status and error and do not keep the others from being created.
+// @Accept json
+// @Produce json
+// @Param body body swagger.ArticlesBulkCreateRequest true "Body"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.ArticleBulkResults
+// @Failure 400,401,500 {object} swagger.Error
+// @Router /articles/bulk [post]
+func (h *handler) StoreMany(c echo.Context) error {
+	req := new(struct {
+		Articles []*models.Article `json:"articles"`
+	})
+
+	if err := c.Bind(req); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	if err := h.checkBulkSize(len(req.Articles)); err != nil {
+		return err
+	}
+
+	authorID := utils.GetCtxID(c)
+	for _, a := range req.Articles {
+		if a == nil {
+			return echo.ErrBadRequest
+		}
+
+		a.AuthorID = authorID
+	}
+
+	results, err := h.articleUseCase.StoreMany(req.Articles)
+	if err != nil {
+		h.log.Errorf("article.UseCase.StoreMany: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newBulkResults(results))
+}
+
+// UpdateMany godoc
+// @Tags Articles
+// @Summary Update articles in bulk
+// @Description Updates up to articles.bulk_limit articles in one transaction. An item with
+// @Description updated_at is only applied if the article has not changed since, like If-Match.
+// @Accept json
+// @Produce json
+// @Param body body swagger.ArticlesBulkUpdateRequest true "Body"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.ArticleBulkResults
+// @Failure 400,401,500 {object} swagger.Error
+// @Router /articles/bulk [put]
+func (h *handler) UpdateMany(c echo.Context) error {
+	req := new(struct {
+		Articles []*models.Article `json:"articles"`
+	})
+
+	if err := c.Bind(req); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	if err := h.checkBulkSize(len(req.Articles)); err != nil {
+		return err
+	}
+
+	authorID := utils.GetCtxID(c)
+	for _, a := range req.Articles {
+		if a == nil || a.ID == uuid.Nil {
+			return echo.NewHTTPError(
+				http.StatusBadRequest,
+				"every article must have an id",
+			)
+		}
+
+		a.AuthorID = authorID
+	}
+
+	results, err := h.articleUseCase.UpdateMany(req.Articles)
+	if err != nil {
+		h.log.Errorf("article.UseCase.UpdateMany: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newBulkResults(results))
+}
+
+// DeleteMany godoc
+// @Tags Articles
+// @Summary Delete articles in bulk
+// @Description Deletes up to articles.bulk_limit articles in one transaction.
+// @Accept json
+// @Produce json
+// @Param body body swagger.ArticlesBulkDeleteRequest true "Body"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.ArticleBulkResults
+// @Failure 400,401,500 {object} swagger.Error
+// @Router /articles/bulk [delete]
+func (h *handler) DeleteMany(c echo.Context) error {
+	req := new(struct {
+		IDs []uuid.UUID `json:"ids"`
+	})
+
+	if err := c.Bind(req); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	if err := h.checkBulkSize(len(req.IDs)); err != nil {
+		return err
+	}
+
+	results, err := h.articleUseCase.DeleteMany(req.IDs, utils.GetCtxID(c))
+	if err != nil {
+		h.log.Errorf("article.UseCase.DeleteMany: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newBulkResults(results))
+}
+
+// Restore godoc
+// @Tags Articles
+// @Summary Restore deleted article
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Article
+// @Failure 400,401,404,500 {object} swagger.Error
+// @Router /articles/{id}/restore [post]
+func (h *handler) Restore(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	restoredArticle, err := h.articleUseCase.Restore(models.Article{
+		ID:       id,
+		AuthorID: utils.GetCtxID(c),
+	})
+	if err != nil {
+		h.log.Errorf("article.UseCase.Restore: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, restoredArticle)
+}
+
+// GetRevisions godoc
+// @Tags Articles
+// @Summary Get article revisions
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Success 200 {object} models.ArticleRevisionsList
+// @Failure 400,404,500 {object} swagger.Error
+// @Router /articles/{id}/revisions [get]
+func (h *handler) GetRevisions(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	res, err := h.articleUseCase.GetRevisions(id, utils.GetCtxViewerID(c))
+	if err != nil {
+		h.log.Errorf("article.UseCase.GetRevisions: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &models.ArticleRevisionsList{
+		TotalCount: len(res),
+		Revisions:  res,
+	})
+}
+
+// GetRevision godoc
+// @Tags Articles
+// @Summary Get article revision
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Param rev path int true "Revision"
+// @Success 200 {object} models.ArticleRevision
+// @Failure 400,404,500 {object} swagger.Error
+// @Router /articles/{id}/revisions/{rev} [get]
+func (h *handler) GetRevision(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	rev, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	revision, err := h.articleUseCase.GetRevision(
+		id,
+		rev,
+		utils.GetCtxViewerID(c),
+	)
+	if err != nil {
+		h.log.Errorf("article.UseCase.GetRevision: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, revision)
+}
+
+// DiffRevisions godoc
+// @Tags Articles
+// @Summary Diff two article revisions
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Param from query int true "Base revision"
+// @Param to query int true "Target revision"
+// @Success 200 {object} models.ArticleRevisionDiff
+// @Failure 400,404,500 {object} swagger.Error
+// @Router /articles/{id}/revisions/diff [get]
+func (h *handler) DiffRevisions(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	from, err := strconv.Atoi(c.QueryParam("from"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid from revision")
+	}
+
+	to, err := strconv.Atoi(c.QueryParam("to"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid to revision")
+	}
+
+	res, err := h.articleUseCase.DiffRevisions(
+		id,
+		from,
+		to,
+		utils.GetCtxViewerID(c),
+	)
+	if err != nil {
+		h.log.Errorf("article.UseCase.DiffRevisions: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// RestoreRevision godoc
+// @Tags Articles
+// @Summary Restore article revision
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Param rev path int true "Revision"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Article
+// @Failure 400,401,404,500 {object} swagger.Error
+// @Router /articles/{id}/revisions/{rev}/restore [post]
+func (h *handler) RestoreRevision(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	rev, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	updatedArticle, err := h.articleUseCase.RestoreRevision(models.Article{
+		ID:       id,
+		AuthorID: utils.GetCtxID(c),
+	}, rev)
+	if err != nil {
+		h.log.Errorf("article.UseCase.RestoreRevision: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, updatedArticle)
+}
+
+// GetTags godoc
+// @Tags Articles
+// @Summary Get tags with usage counts
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.TagsList
+// @Failure 500 {object} swagger.Error
+// @Router /tags [get]
+func (h *handler) GetTags(c echo.Context) error {
+	res, err := h.articleUseCase.GetTags()
+	if err != nil {
+		h.log.Errorf("article.UseCase.GetTags: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &models.TagsList{
+		TotalCount: len(res),
+		Tags:       res,
+	})
+}
+
+// Like godoc
+// @Tags Articles
+// @Summary Like article
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 400,401,404,500 {object} swagger.Error
+// @Router /articles/{id}/like [put]
+func (h *handler) Like(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	if err := h.articleUseCase.Like(id, utils.GetCtxID(c)); err != nil {
+		h.log.Errorf("article.UseCase.Like: %v", err)
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Unlike godoc
+// @Tags Articles
+// @Summary Unlike article
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 400,401,404,500 {object} swagger.Error
+// @Router /articles/{id}/like [delete]
+func (h *handler) Unlike(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	if err := h.articleUseCase.Unlike(id, utils.GetCtxID(c)); err != nil {
+		h.log.Errorf("article.UseCase.Unlike: %v", err)
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Bookmark godoc
+// @Tags Articles
+// @Summary Bookmark article
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 400,401,404,500 {object} swagger.Error
+// @Router /articles/{id}/bookmark [put]
+func (h *handler) Bookmark(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	if err := h.articleUseCase.Bookmark(id, utils.GetCtxID(c)); err != nil {
+		h.log.Errorf("article.UseCase.Bookmark: %v", err)
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Unbookmark godoc
+// @Tags Articles
+// @Summary Remove article bookmark
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 400,401,404,500 {object} swagger.Error
+// @Router /articles/{id}/bookmark [delete]
+func (h *handler) Unbookmark(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	if err := h.articleUseCase.Unbookmark(id, utils.GetCtxID(c)); err != nil {
+		h.log.Errorf("article.UseCase.Unbookmark: %v", err)
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetBookmarks godoc
+// @Tags Articles
+// @Summary Get bookmarked articles
+// @Description Returns the caller's bookmarks, most recent first.
+// @Accept json
+// @Produce json
+// @Param include query string false "Embed related resources" Enums(author)
+// @Security ApiKeyAuth
+// @Success 200 {object} models.ArticlesList
+// @Failure 400,401,500 {object} swagger.Error
+// @Router /bookmarks [get]
+func (h *handler) GetBookmarks(c echo.Context) error {
+	include, err := includeAuthor(c)
+	if err != nil {
+		return err
+	}
+
+	res, err := h.articleUseCase.GetBookmarks(utils.GetCtxID(c))
+	if err != nil {
+		h.log.Errorf("article.UseCase.GetBookmarks: %v", err)
+		return err
+	}
+
+	if include {
+		if _, err := h.setAuthors(res); err != nil {
+			return err
+		}
+	}
+
+	return c.JSON(http.StatusOK, &models.ArticlesList{
+		TotalCount: len(res),
+		Articles:   res,
+	})
+}
+
+// checkBulkSize rejects empty bulk requests and those over the limit.
+func (h *handler) checkBulkSize(n int) error {
+	if n == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "no items given")
+	}
+
+	if n > h.cfg.Articles.BulkLimit {
+		return echo.NewHTTPError(
+			http.StatusBadRequest,
+			"at most "+strconv.Itoa(h.cfg.Articles.BulkLimit)+" items are allowed",
+		)
+	}
+
+	return nil
+}
+
+func newBulkResults(results []models.ArticleBulkResult) *models.ArticleBulkResults {
+	res := &models.ArticleBulkResults{Results: results}
+
+	for _, r := range results {
+		if r.Status < http.StatusBadRequest {
+			res.Succeeded++
+		} else {
+			res.Failed++
+		}
+	}
+
+	return res
+}
+
+// getVersion identifies the state of an article as the caller sees it,
+// including the counters and reactions that do not touch updated_at.
+func getVersion(a *models.Article) string {
+	version := a.ID.String() + utils.ETag(a.UpdatedAt) + strconv.Itoa(a.LikesCount)
+
+	if a.Liked != nil && *a.Liked {
+		version += "l"
+	}
+
+	if a.Bookmarked != nil && *a.Bookmarked {
+		version += "b"
+	}
+
+	return version
+}
+
+// setAuthors embeds the authors of the articles and returns them by ID.
+// Authors whose accounts have been deleted are left out.
+func (h *handler) setAuthors(
+	articles []models.Article,
+) (map[uuid.UUID]models.User, error) {
+	seen := make(map[uuid.UUID]bool, len(articles))
+	ids := make([]uuid.UUID, 0, len(articles))
+	for _, a := range articles {
+		if !seen[a.AuthorID] {
+			seen[a.AuthorID] = true
+			ids = append(ids, a.AuthorID)
+		}
+	}
+
+	users, err := h.userUseCase.GetByIDs(ids)
+	if err != nil {
+		h.log.Errorf("auth.UseCase.GetByIDs: %v", err)
+		return nil, err
+	}
+
+	authors := make(map[uuid.UUID]models.User, len(users))
+	for _, u := range users {
+		authors[u.ID] = u
+	}
+
+	for i := range articles {
+		if author, ok := authors[articles[i].AuthorID]; ok {
+			summary := author.Summary()
+			articles[i].Author = &summary
+		}
+	}
+
+	return authors, nil
+}
+
+// includeAuthor reports whether the request asks for the authors to be
+// embedded with ?include=author.
+func includeAuthor(c echo.Context) (bool, error) {
+	include := false
+
+	for _, param := range c.QueryParams()["include"] {
+		for _, v := range strings.Split(param, ",") {
+			switch strings.TrimSpace(v) {
+			case "author":
+				include = true
+			case "":
+			default:
+				return false, echo.NewHTTPError(
+					http.StatusBadRequest,
+					"include must be author",
+				)
+			}
+		}
+	}
+
+	return include, nil
+}
+
+// getTags collects the tag filter from both repeated and comma-separated
+// tag query parameters, e.g. ?tag=go&tag=sql or ?tag=go,sql.
+func getTags(c echo.Context) []string {
+	var tags []string
+
+	for _, param := range c.QueryParams()["tag"] {
+		tags = append(tags, strings.Split(param, ",")...)
+	}
+
+	return models.NormalizeTags(tags)
+}
@@ -1,15 +1,140 @@
 package repository
 
+// articleTags aggregates the tag names of the current articles row, so it
+// can be used both in SELECT lists and in RETURNING clauses.
+const articleTags = `COALESCE((SELECT array_agg(t.name ORDER BY t.name) 
+									FROM article_tags atg JOIN tags t ON t.id = atg.tag_id 
+									WHERE atg.article_id = articles.id), '{}') AS tags`
+
 var (
-	getArticleQuery    = `SELECT * FROM articles WHERE id = $1`
-	getArticlesQuery   = `SELECT * FROM articles ORDER BY created_at DESC`
-	createArticleQuery = `INSERT INTO articles (author_id, title, "desc") 
-									VALUES ($1, $2, $3) RETURNING *`
+	getArticleQuery = `SELECT *, ` + articleTags + ` FROM articles 
+									WHERE id = $1 AND deleted_at IS NULL`
+	getArticleForUpdateQuery = `SELECT *, ` + articleTags + ` FROM articles 
+									WHERE id = $1 AND deleted_at IS NULL 
+									FOR UPDATE`
+	getArticlesQuery = `SELECT *, ` + articleTags + ` FROM articles 
+									WHERE deleted_at IS NULL 
+									AND (status = 'published' OR author_id = $1) 
+									AND ($3::uuid IS NULL OR author_id = $3) 
+									AND (COALESCE(cardinality($2::text[]), 0) = 0 OR EXISTS (
+										SELECT 1 FROM article_tags atg JOIN tags t ON t.id = atg.tag_id 
+										WHERE atg.article_id = articles.id AND t.name = ANY($2)
+									)) 
+									ORDER BY created_at DESC`
+	// The rows are locked in id order, so that overlapping bulk requests
+	// wait for each other instead of deadlocking.
+	getArticlesForUpdateQuery = `SELECT *, ` + articleTags + ` FROM articles 
+									WHERE id = ANY($1) AND deleted_at IS NULL 
+									ORDER BY id 
+									FOR UPDATE`
+	createArticleQuery = `INSERT INTO articles 
+									(id, author_id, title, slug, "desc", status, published_at, format) 
+									VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *`
+	// createArticlesQuery is followed by one row of values per article.
+	createArticlesQuery = `INSERT INTO articles 
+									(id, author_id, title, slug, "desc", status, published_at, format) 
+									VALUES `
 	updateArticleQuery = `UPDATE articles 
 									SET title = COALESCE(NULLIF($1, ''), title), 
 										"desc" = COALESCE(NULLIF($2, ''), "desc"), 
+										status = $3, 
+										published_at = $4, 
+										slug = COALESCE(NULLIF($7, ''), slug), 
+										format = COALESCE(NULLIF($8, ''), format), 
 										updated_at = now() 
-									WHERE id = $3 RETURNING *`
-	deleteArticleQuery = `DELETE FROM articles WHERE id = $1 
-									AND author_id = $2`
+									WHERE id = $5 AND deleted_at IS NULL 
+									AND ($6::timestamptz IS NULL OR updated_at = $6) 
+									RETURNING *, ` + articleTags
+	articleExistsQuery = `SELECT EXISTS (SELECT 1 FROM articles 
+									WHERE id = $1 AND deleted_at IS NULL)`
+	publishDueArticlesQuery = `UPDATE articles 
+									SET status = 'published', updated_at = now() 
+									WHERE id IN (
+										SELECT id FROM articles 
+										WHERE status = 'scheduled' AND published_at <= now() 
+										AND deleted_at IS NULL 
+										ORDER BY published_at LIMIT $1 
+										FOR UPDATE SKIP LOCKED
+									) RETURNING *, ` + articleTags
+	deleteArticleQuery = `UPDATE articles SET deleted_at = now() 
+									WHERE id = $1 AND author_id = $2 
+									AND deleted_at IS NULL`
+	deleteArticlesQuery = `UPDATE articles SET deleted_at = now() 
+									WHERE id = ANY($1) AND author_id = $2 
+									AND deleted_at IS NULL 
+									RETURNING id`
+	restoreArticleQuery = `UPDATE articles SET deleted_at = NULL 
+									WHERE id = $1 AND author_id = $2 
+									AND deleted_at IS NOT NULL 
+									RETURNING *, ` + articleTags
+	purgeArticlesQuery = `DELETE FROM articles WHERE deleted_at < $1 
+									AND NOT EXISTS (SELECT 1 FROM users u 
+										WHERE u.id = articles.author_id AND u.deleted_at = articles.deleted_at)`
+
+	getArticleIDBySlugQuery = `SELECT article_id FROM article_slugs WHERE slug = $1`
+	createSlugQuery         = `INSERT INTO article_slugs (slug, article_id) 
+									VALUES ($1, $2) 
+									ON CONFLICT (slug) DO UPDATE SET created_at = now() 
+									WHERE article_slugs.article_id = EXCLUDED.article_id 
+									RETURNING slug`
+
+	getRevisionQuery = `SELECT * FROM article_revisions 
+									WHERE article_id = $1 AND rev = $2`
+	getRevisionsQuery = `SELECT * FROM article_revisions 
+									WHERE article_id = $1 ORDER BY rev DESC`
+	createRevisionQuery = `INSERT INTO article_revisions 
+									(article_id, rev, editor_id, title, "desc") 
+									SELECT $1, COALESCE(MAX(rev), 0) + 1, $2, $3, $4 
+									FROM article_revisions WHERE article_id = $1`
+	createNextRevisionsQuery = `INSERT INTO article_revisions 
+								(article_id, rev, editor_id, title, "desc") 
+								SELECT a.id, COALESCE((SELECT MAX(r.rev) FROM article_revisions r 
+									WHERE r.article_id = a.id), 0) + 1, a.author_id, a.title, a."desc" 
+								FROM articles a WHERE a.id = ANY($1)`
+	createFirstRevisionsQuery = `INSERT INTO article_revisions 
+									(article_id, rev, editor_id, title, "desc") 
+									SELECT r.article_id, 1, r.editor_id, r.title, r."desc" 
+									FROM unnest($1::uuid[], $2::uuid[], $3::text[], $4::text[]) 
+										AS r(article_id, editor_id, title, "desc")`
+
+	likeArticleQuery = `INSERT INTO article_likes (article_id, user_id) 
+									VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	unlikeArticleQuery = `DELETE FROM article_likes 
+									WHERE article_id = $1 AND user_id = $2`
+	bookmarkArticleQuery = `INSERT INTO article_bookmarks (article_id, user_id) 
+									VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	unbookmarkArticleQuery = `DELETE FROM article_bookmarks 
+									WHERE article_id = $1 AND user_id = $2`
+	getBookmarksQuery = `SELECT articles.*, ` + articleTags + ` FROM articles 
+									JOIN article_bookmarks ab ON ab.article_id = articles.id 
+									WHERE ab.user_id = $1 AND articles.deleted_at IS NULL 
+									AND (articles.status = 'published' OR articles.author_id = $1) 
+									ORDER BY ab.created_at DESC`
+	getReactionsQuery = `SELECT a.id AS article_id, 
+										EXISTS (SELECT 1 FROM article_likes l 
+											WHERE l.article_id = a.id AND l.user_id = $1) AS liked, 
+										EXISTS (SELECT 1 FROM article_bookmarks b 
+											WHERE b.article_id = a.id AND b.user_id = $1) AS bookmarked 
+									FROM unnest($2::uuid[]) AS a(id)`
+	reconcileLikesQuery = `UPDATE articles 
+									SET likes_count = (SELECT COUNT(*) FROM article_likes l 
+										WHERE l.article_id = articles.id) 
+									WHERE id = ANY($1::uuid[]) 
+									RETURNING *, ` + articleTags
+
+	getTagsQuery = `SELECT t.name, COUNT(*) AS count FROM tags t 
+									JOIN article_tags atg ON atg.tag_id = t.id 
+									JOIN articles a ON a.id = atg.article_id 
+									WHERE a.status = 'published' AND a.deleted_at IS NULL 
+									GROUP BY t.name ORDER BY count DESC, t.name`
+	createTagsQuery = `INSERT INTO tags (name) 
+									SELECT unnest($1::text[]) 
+									ON CONFLICT (name) DO NOTHING`
+	deleteArticleTagsQuery = `DELETE FROM article_tags WHERE article_id = $1`
+	createArticleTagsQuery = `INSERT INTO article_tags (article_id, tag_id) 
+									SELECT $1, id FROM tags WHERE name = ANY($2)`
+	createArticlesTagsQuery = `INSERT INTO article_tags (article_id, tag_id) 
+									SELECT atg.article_id, t.id 
+									FROM unnest($1::uuid[], $2::text[]) AS atg(article_id, name) 
+									JOIN tags t ON t.name = atg.name`
 )
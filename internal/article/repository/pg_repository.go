@@ -2,28 +2,39 @@ package repository
 
 import (
 	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
+	"github.com/lib/pq"
 	"github.com/slavtov/clean-architecture/internal/domain/models"
 	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/pkg/store/postgres"
 )
 
 type pgRepository struct {
-	db *sqlx.DB
+	db postgres.DB
 }
 
-func NewPGRepository(db *sqlx.DB) repositories.PGArticleRepository {
+func NewPGRepository(db postgres.DB) repositories.PGArticleRepository {
 	return &pgRepository{db}
 }
 
-func (r *pgRepository) GetAll() ([]models.Article, error) {
+func (r *pgRepository) GetAll(
+	filter *models.ArticleFilter,
+) ([]models.Article, error) {
 	var articles []models.Article
 
 	if err := r.db.Select(
 		&articles,
 		getArticlesQuery,
+		filter.ViewerID,
+		pq.Array(filter.Tags),
+		filter.AuthorID,
 	); err != nil {
 		return articles, echo.ErrInternalServerError
 	}
@@ -49,35 +60,264 @@ func (r *pgRepository) GetByID(id uuid.UUID) (models.Article, error) {
 	return article, nil
 }
 
+func (r *pgRepository) GetByIDForUpdate(id uuid.UUID) (models.Article, error) {
+	var article models.Article
+
+	if err := r.db.Get(
+		&article,
+		getArticleForUpdateQuery,
+		id,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return article, echo.ErrNotFound
+		}
+
+		return article, echo.ErrInternalServerError
+	}
+
+	return article, nil
+}
+
+func (r *pgRepository) GetForUpdate(ids []uuid.UUID) ([]models.Article, error) {
+	var articles []models.Article
+
+	if err := r.db.Select(
+		&articles,
+		getArticlesForUpdateQuery,
+		pq.Array(ids),
+	); err != nil {
+		return articles, echo.ErrInternalServerError
+	}
+
+	return articles, nil
+}
+
 func (r *pgRepository) Store(a *models.Article) (*models.Article, error) {
 	var article models.Article
 
-	if err := r.db.QueryRowx(
-		createArticleQuery,
-		a.AuthorID,
-		a.Title,
-		a.Desc,
-	).StructScan(&article); err != nil {
-		return nil, echo.ErrBadRequest
+	err := postgres.WithTx(r.db, func(tx *sqlx.Tx) error {
+		id := uuid.New()
+
+		slug, err := assignSlug(tx, id, a.Slug)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.QueryRowx(
+			createArticleQuery,
+			id,
+			a.AuthorID,
+			a.Title,
+			slug,
+			a.Desc,
+			a.Status,
+			a.PublishedAt,
+			a.Format,
+		).StructScan(&article); err != nil {
+			return echo.ErrBadRequest
+		}
+
+		article.Tags = pq.StringArray{}
+		if len(a.Tags) > 0 {
+			if err := setTags(tx, article.ID, a.Tags); err != nil {
+				return err
+			}
+
+			article.Tags = a.Tags
+		}
+
+		return createRevision(tx, &article, a.AuthorID)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &article, nil
 }
 
+// StoreMany writes each table with a single multi-row statement. Only the
+// slugs are reserved one at a time, as each may need several attempts.
+func (r *pgRepository) StoreMany(
+	articles []*models.Article,
+) ([]models.Article, error) {
+	if len(articles) == 0 {
+		return nil, nil
+	}
+
+	res := make([]models.Article, len(articles))
+
+	err := postgres.WithTx(r.db, func(tx *sqlx.Tx) error {
+		var (
+			ids       = make([]uuid.UUID, 0, len(articles))
+			authorIDs = make([]uuid.UUID, 0, len(articles))
+			titles    = make([]string, 0, len(articles))
+			descs     = make([]string, 0, len(articles))
+			rows      = make([]string, 0, len(articles))
+			args      = make([]interface{}, 0, len(articles)*8)
+			tagIDs    []uuid.UUID
+			tags      []string
+		)
+
+		for _, a := range articles {
+			id := uuid.New()
+
+			slug, err := assignSlug(tx, id, a.Slug)
+			if err != nil {
+				return err
+			}
+
+			n := len(args)
+			rows = append(rows, fmt.Sprintf(
+				"($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+				n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8,
+			))
+			args = append(
+				args,
+				id,
+				a.AuthorID,
+				a.Title,
+				slug,
+				a.Desc,
+				a.Status,
+				a.PublishedAt,
+				a.Format,
+			)
+
+			ids = append(ids, id)
+			authorIDs = append(authorIDs, a.AuthorID)
+			titles = append(titles, a.Title)
+			descs = append(descs, a.Desc)
+
+			for _, tag := range a.Tags {
+				tagIDs = append(tagIDs, id)
+				tags = append(tags, tag)
+			}
+		}
+
+		index := make(map[uuid.UUID]int, len(ids))
+		for i, id := range ids {
+			index[id] = i
+		}
+
+		created, err := tx.Queryx(
+			createArticlesQuery+strings.Join(rows, ", ")+" RETURNING *",
+			args...,
+		)
+		if err != nil {
+			return echo.ErrBadRequest
+		}
+		defer created.Close()
+
+		for created.Next() {
+			var article models.Article
+			if err := created.StructScan(&article); err != nil {
+				return echo.ErrInternalServerError
+			}
+
+			i := index[article.ID]
+			article.Tags = pq.StringArray{}
+			if len(articles[i].Tags) > 0 {
+				article.Tags = articles[i].Tags
+			}
+			res[i] = article
+		}
+
+		if err := created.Err(); err != nil {
+			return echo.ErrBadRequest
+		}
+
+		if len(tags) > 0 {
+			if _, err := tx.Exec(createTagsQuery, pq.Array(tags)); err != nil {
+				return echo.ErrBadRequest
+			}
+
+			if _, err := tx.Exec(
+				createArticlesTagsQuery,
+				pq.Array(tagIDs),
+				pq.Array(tags),
+			); err != nil {
+				return echo.ErrInternalServerError
+			}
+		}
+
+		if _, err := tx.Exec(
+			createFirstRevisionsQuery,
+			pq.Array(ids),
+			pq.Array(authorIDs),
+			pq.Array(titles),
+			pq.Array(descs),
+		); err != nil {
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 func (r *pgRepository) Update(a *models.Article) (*models.Article, error) {
 	var article models.Article
 
-	if err := r.db.QueryRowx(
-		updateArticleQuery,
-		a.Title,
-		a.Desc,
-		a.ID,
-	).StructScan(&article); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, echo.ErrNotFound
+	err := postgres.WithTx(r.db, func(tx *sqlx.Tx) error {
+		slug := ""
+		if a.Slug != "" {
+			var err error
+			if slug, err = assignSlug(tx, a.ID, a.Slug); err != nil {
+				return err
+			}
 		}
 
-		return nil, echo.ErrBadRequest
+		if err := tx.QueryRowx(
+			updateArticleQuery,
+			a.Title,
+			a.Desc,
+			a.Status,
+			a.PublishedAt,
+			a.ID,
+			postgres.NullTime(a.UpdatedAt),
+			slug,
+			a.Format,
+		).StructScan(&article); err != nil {
+			if err != sql.ErrNoRows {
+				return echo.ErrBadRequest
+			}
+
+			if a.UpdatedAt.IsZero() {
+				return echo.ErrNotFound
+			}
+
+			var exists bool
+			if err := tx.Get(
+				&exists,
+				articleExistsQuery,
+				a.ID,
+			); err != nil {
+				return echo.ErrInternalServerError
+			}
+
+			if exists {
+				return repositories.ErrArticleConflict
+			}
+
+			return echo.ErrNotFound
+		}
+
+		if a.Tags != nil {
+			if err := setTags(tx, article.ID, a.Tags); err != nil {
+				return err
+			}
+
+			article.Tags = a.Tags
+		}
+
+		return createRevision(tx, &article, a.AuthorID)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &article, nil
@@ -104,3 +344,314 @@ func (r *pgRepository) Delete(a models.Article) error {
 
 	return nil
 }
+
+func (r *pgRepository) DeleteMany(
+	ids []uuid.UUID,
+	authorID uuid.UUID,
+) ([]uuid.UUID, error) {
+	var deleted []uuid.UUID
+
+	if err := r.db.Select(
+		&deleted,
+		deleteArticlesQuery,
+		pq.Array(ids),
+		authorID,
+	); err != nil {
+		return nil, echo.ErrBadRequest
+	}
+
+	return deleted, nil
+}
+
+func (r *pgRepository) Restore(a models.Article) (*models.Article, error) {
+	var article models.Article
+
+	if err := r.db.QueryRowx(
+		restoreArticleQuery,
+		a.ID,
+		a.AuthorID,
+	).StructScan(&article); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, echo.ErrNotFound
+		}
+
+		return nil, echo.ErrBadRequest
+	}
+
+	return &article, nil
+}
+
+func (r *pgRepository) Purge(before time.Time) (int64, error) {
+	res, err := r.db.Exec(purgeArticlesQuery, before)
+	if err != nil {
+		return 0, echo.ErrInternalServerError
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, echo.ErrInternalServerError
+	}
+
+	return rowsAffected, nil
+}
+
+func (r *pgRepository) GetIDBySlug(slug string) (uuid.UUID, error) {
+	var id uuid.UUID
+
+	if err := r.db.Get(
+		&id,
+		getArticleIDBySlugQuery,
+		slug,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return id, echo.ErrNotFound
+		}
+
+		return id, echo.ErrBadRequest
+	}
+
+	return id, nil
+}
+
+// PublishDue publishes the scheduled articles whose time has come and
+// records the publication as a revision by the author.
+func (r *pgRepository) PublishDue(limit int) ([]models.Article, error) {
+	var articles []models.Article
+
+	err := postgres.WithTx(r.db, func(tx *sqlx.Tx) error {
+		if err := tx.Select(
+			&articles,
+			publishDueArticlesQuery,
+			limit,
+		); err != nil {
+			return echo.ErrInternalServerError
+		}
+
+		if len(articles) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, 0, len(articles))
+		for _, a := range articles {
+			ids = append(ids, a.ID)
+		}
+
+		if _, err := tx.Exec(createNextRevisionsQuery, pq.Array(ids)); err != nil {
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+func (r *pgRepository) GetRevisions(id uuid.UUID) ([]models.ArticleRevision, error) {
+	var revisions []models.ArticleRevision
+
+	if err := r.db.Select(
+		&revisions,
+		getRevisionsQuery,
+		id,
+	); err != nil {
+		return revisions, echo.ErrInternalServerError
+	}
+
+	return revisions, nil
+}
+
+func (r *pgRepository) GetRevision(
+	id uuid.UUID,
+	rev int,
+) (models.ArticleRevision, error) {
+	var revision models.ArticleRevision
+
+	if err := r.db.Get(
+		&revision,
+		getRevisionQuery,
+		id,
+		rev,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return revision, echo.ErrNotFound
+		}
+
+		return revision, echo.ErrBadRequest
+	}
+
+	return revision, nil
+}
+
+func (r *pgRepository) GetTags() ([]models.Tag, error) {
+	var tags []models.Tag
+
+	if err := r.db.Select(
+		&tags,
+		getTagsQuery,
+	); err != nil {
+		return tags, echo.ErrInternalServerError
+	}
+
+	return tags, nil
+}
+
+func (r *pgRepository) Like(id uuid.UUID, userID uuid.UUID) (bool, error) {
+	return r.react(likeArticleQuery, id, userID)
+}
+
+func (r *pgRepository) Unlike(id uuid.UUID, userID uuid.UUID) (bool, error) {
+	return r.react(unlikeArticleQuery, id, userID)
+}
+
+func (r *pgRepository) Bookmark(id uuid.UUID, userID uuid.UUID) error {
+	_, err := r.react(bookmarkArticleQuery, id, userID)
+	return err
+}
+
+func (r *pgRepository) Unbookmark(id uuid.UUID, userID uuid.UUID) error {
+	_, err := r.react(unbookmarkArticleQuery, id, userID)
+	return err
+}
+
+func (r *pgRepository) GetBookmarks(userID uuid.UUID) ([]models.Article, error) {
+	var articles []models.Article
+
+	if err := r.db.Select(
+		&articles,
+		getBookmarksQuery,
+		userID,
+	); err != nil {
+		return articles, echo.ErrInternalServerError
+	}
+
+	return articles, nil
+}
+
+func (r *pgRepository) GetReactions(
+	userID uuid.UUID,
+	ids []uuid.UUID,
+) ([]models.ArticleReactions, error) {
+	var reactions []models.ArticleReactions
+
+	if err := r.db.Select(
+		&reactions,
+		getReactionsQuery,
+		userID,
+		pq.Array(ids),
+	); err != nil {
+		return reactions, echo.ErrInternalServerError
+	}
+
+	return reactions, nil
+}
+
+func (r *pgRepository) ReconcileLikes(ids []uuid.UUID) ([]models.Article, error) {
+	var articles []models.Article
+
+	if err := r.db.Select(
+		&articles,
+		reconcileLikesQuery,
+		pq.Array(ids),
+	); err != nil {
+		return articles, echo.ErrInternalServerError
+	}
+
+	return articles, nil
+}
+
+// react runs an idempotent insert or delete of a user relation and
+// reports whether it changed anything.
+func (r *pgRepository) react(
+	query string,
+	id uuid.UUID,
+	userID uuid.UUID,
+) (bool, error) {
+	res, err := r.db.Exec(query, id, userID)
+	if err != nil {
+		return false, echo.ErrBadRequest
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, echo.ErrInternalServerError
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// createRevision records the current state of the article. It has to run
+// in the same transaction as the write that produced that state.
+func createRevision(
+	tx *sqlx.Tx,
+	article *models.Article,
+	editorID uuid.UUID,
+) error {
+	if _, err := tx.Exec(
+		createRevisionQuery,
+		article.ID,
+		editorID,
+		article.Title,
+		article.Desc,
+	); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// setTags replaces the tags of the article, creating missing ones.
+func setTags(tx *sqlx.Tx, id uuid.UUID, tags []string) error {
+	if _, err := tx.Exec(deleteArticleTagsQuery, id); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec(createTagsQuery, pq.Array(tags)); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	if _, err := tx.Exec(
+		createArticleTagsQuery,
+		id,
+		pq.Array(tags),
+	); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+const maxSlugAttempts = 100
+
+// assignSlug reserves the first free variant of base (base, base-2, ...)
+// for the article. Slugs are never released, so links to previous titles
+// keep resolving to the same article.
+func assignSlug(tx *sqlx.Tx, id uuid.UUID, base string) (string, error) {
+	for i := 1; i <= maxSlugAttempts+1; i++ {
+		candidate := base
+		switch {
+		case i > maxSlugAttempts:
+			candidate = fmt.Sprintf("%s-%s", base, id.String()[:8])
+		case i > 1:
+			candidate = fmt.Sprintf("%s-%d", base, i)
+		}
+
+		var slug string
+		err := tx.Get(&slug, createSlugQuery, candidate, id)
+		if err == nil {
+			return slug, nil
+		}
+
+		if err != sql.ErrNoRows {
+			return "", echo.ErrInternalServerError
+		}
+	}
+
+	return "", echo.NewHTTPError(http.StatusConflict, "slug is not available")
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"encoding/json"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,7 +17,13 @@ type redisRepository struct {
 	redis redis.Store
 }
 
-const prefix = "articles"
+const (
+	prefix     = "articles"
+	slugPrefix = "slugs"
+	htmlPrefix = "html"
+	likesKey   = "likes"
+	pendingKey = "pending"
+)
 
 func NewRedisRepository(rdb redis.Store) repositories.RedisArticleRepository {
 	return &redisRepository{rdb}
@@ -37,6 +44,15 @@ func (r *redisRepository) GetByID(id uuid.UUID) (models.Article, error) {
 	return article, nil
 }
 
+func (r *redisRepository) GetIDBySlug(slug string) (uuid.UUID, error) {
+	res, err := r.redis.Get(utils.GetRedisKey(prefix, slugPrefix, slug))
+	if err != nil {
+		return uuid.Nil, echo.ErrNotFound
+	}
+
+	return uuid.Parse(res)
+}
+
 func (r *redisRepository) SetArticle(
 	article *models.Article,
 	exp time.Duration,
@@ -53,6 +69,105 @@ func (r *redisRepository) SetArticle(
 		return echo.ErrInternalServerError
 	}
 
+	if article.Slug == "" {
+		return nil
+	}
+
+	return r.SetSlug(article.Slug, article.ID, exp)
+}
+
+// SetArticles caches the articles with their slugs and rendered
+// descriptions in one round trip.
+func (r *redisRepository) SetArticles(
+	articles []*models.Article,
+	exp time.Duration,
+) error {
+	values := make(map[string]interface{}, len(articles)*3)
+
+	for _, article := range articles {
+		res, err := json.Marshal(article)
+		if err != nil {
+			return echo.ErrInternalServerError
+		}
+
+		values[utils.GetRedisKey(prefix, article.ID.String())] = res
+
+		if article.Slug != "" {
+			values[utils.GetRedisKey(
+				prefix,
+				slugPrefix,
+				article.Slug,
+			)] = article.ID.String()
+		}
+
+		if article.DescHTML != "" {
+			values[getHTMLKey(article)] = article.DescHTML
+		}
+	}
+
+	if err := r.redis.SetMany(values, exp); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (r *redisRepository) SetSlug(
+	slug string,
+	id uuid.UUID,
+	exp time.Duration,
+) error {
+	if err := r.redis.Set(utils.GetRedisKey(
+		prefix,
+		slugPrefix,
+		slug,
+	), id.String(), exp); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// GetDescHTML returns the rendered descriptions of the articles, or an
+// empty string for the ones that are not cached. Rendered descriptions are
+// keyed by article version, so a cached one is never stale.
+func (r *redisRepository) GetDescHTML(articles []*models.Article) ([]string, error) {
+	res := make([]string, len(articles))
+	if len(articles) == 0 {
+		return res, nil
+	}
+
+	keys := make([]string, 0, len(articles))
+	for _, a := range articles {
+		keys = append(keys, getHTMLKey(a))
+	}
+
+	values, err := r.redis.MGet(keys...)
+	if err != nil {
+		return res, echo.ErrInternalServerError
+	}
+
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			res[i] = s
+		}
+	}
+
+	return res, nil
+}
+
+func (r *redisRepository) SetDescHTML(
+	article *models.Article,
+	exp time.Duration,
+) error {
+	if err := r.redis.Set(
+		getHTMLKey(article),
+		article.DescHTML,
+		exp,
+	); err != nil {
+		return echo.ErrInternalServerError
+	}
+
 	return nil
 }
 
@@ -66,3 +181,126 @@ func (r *redisRepository) Delete(id uuid.UUID) error {
 
 	return nil
 }
+
+func (r *redisRepository) DeleteMany(ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, utils.GetRedisKey(prefix, id.String()))
+	}
+
+	if err := r.redis.Del(keys...); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// Likes are counted in two hashes of per-article deltas on top of
+// articles.likes_count: articles:likes collects new likes, and
+// articles:likes:pending holds the ones being reconciled to Postgres.
+
+func (r *redisRepository) IncrLikes(id uuid.UUID, incr int64) error {
+	if err := r.redis.HIncrBy(
+		utils.GetRedisKey(prefix, likesKey),
+		id.String(),
+		incr,
+	); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (r *redisRepository) GetLikes(ids []uuid.UUID) ([]int64, error) {
+	res := make([]int64, len(ids))
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	fields := make([]string, 0, len(ids))
+	for _, id := range ids {
+		fields = append(fields, id.String())
+	}
+
+	for _, key := range []string{
+		utils.GetRedisKey(prefix, likesKey),
+		utils.GetRedisKey(prefix, likesKey, pendingKey),
+	} {
+		values, err := r.redis.HMGet(key, fields...)
+		if err != nil {
+			return res, echo.ErrInternalServerError
+		}
+
+		for i, value := range values {
+			s, ok := value.(string)
+			if !ok {
+				continue
+			}
+
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return res, echo.ErrInternalServerError
+			}
+
+			res[i] += n
+		}
+	}
+
+	return res, nil
+}
+
+// TakeLikes moves the collected deltas to the pending hash and returns the
+// articles they belong to. Deltas left pending by an unfinished
+// reconciliation are returned again instead.
+func (r *redisRepository) TakeLikes() ([]uuid.UUID, error) {
+	pending := utils.GetRedisKey(prefix, likesKey, pendingKey)
+
+	if _, err := r.redis.RenameNX(
+		utils.GetRedisKey(prefix, likesKey),
+		pending,
+	); err != nil {
+		return nil, echo.ErrInternalServerError
+	}
+
+	res, err := r.redis.HGetAll(pending)
+	if err != nil {
+		return nil, echo.ErrInternalServerError
+	}
+
+	ids := make([]uuid.UUID, 0, len(res))
+	for field := range res {
+		id, err := uuid.Parse(field)
+		if err != nil {
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (r *redisRepository) ClearLikes() error {
+	if err := r.redis.Del(
+		utils.GetRedisKey(prefix, likesKey, pendingKey),
+	); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// getHTMLKey returns the key of the rendered description of an article
+// version, articles:html:<article id>:<updated_at in hex microseconds>.
+func getHTMLKey(article *models.Article) string {
+	return utils.GetRedisKey(
+		prefix,
+		htmlPrefix,
+		article.ID.String(),
+		strconv.FormatInt(article.UpdatedAt.UnixMicro(), 16),
+	)
+}
@@ -0,0 +1,329 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+)
+
+// Bulk operations apply every valid item in a single transaction. Items
+// that fail validation, do not exist or belong to someone else are
+// reported in their own result and do not keep the others from being
+// applied; any other error fails the whole request.
+
+type bulkResults []models.ArticleBulkResult
+
+func newBulkResults(n int) bulkResults {
+	res := make(bulkResults, n)
+	for i := range res {
+		res[i].Index = i
+	}
+
+	return res
+}
+
+func (r bulkResults) fail(i int, err error) {
+	r[i].Status = http.StatusInternalServerError
+	r[i].Error = http.StatusText(http.StatusInternalServerError)
+
+	if he, ok := err.(*echo.HTTPError); ok {
+		r[i].Status = he.Code
+		r[i].Error = fmt.Sprint(he.Message)
+	}
+}
+
+func (r bulkResults) failed(i int) bool {
+	return r[i].Status != 0
+}
+
+func (r bulkResults) succeed(i int, status int, article *models.Article) {
+	r[i].ID = &article.ID
+	r[i].Status = status
+	r[i].Article = article
+}
+
+func (u *usecase) StoreMany(
+	articles []*models.Article,
+) ([]models.ArticleBulkResult, error) {
+	results := newBulkResults(len(articles))
+	now := time.Now()
+
+	valid := make([]*models.Article, 0, len(articles))
+	index := make([]int, 0, len(articles))
+
+	for i, a := range articles {
+		if err := prepareStore(a, now); err != nil {
+			results.fail(i, err)
+			continue
+		}
+
+		valid = append(valid, a)
+		index = append(index, i)
+	}
+
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	var created []models.Article
+
+	if err := u.uow.Do(nil, func(r *repositories.TxRepositories) error {
+		var err error
+		if created, err = r.Articles.StoreMany(valid); err != nil {
+			u.log.Errorf("article.pgRepository.StoreMany: %v", err)
+			return err
+		}
+
+		for i := range created {
+			if err := u.emit(
+				r,
+				models.EventArticleCreated,
+				created[i].ID,
+				&created[i],
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	res := make([]*models.Article, 0, len(created))
+	for j := range created {
+		res = append(res, &created[j])
+		results.succeed(index[j], http.StatusCreated, &created[j])
+	}
+
+	u.cacheMany(res)
+
+	return results, nil
+}
+
+func (u *usecase) UpdateMany(
+	articles []*models.Article,
+) ([]models.ArticleBulkResult, error) {
+	results := newBulkResults(len(articles))
+
+	seen := make(map[uuid.UUID]bool, len(articles))
+	ids := make([]uuid.UUID, 0, len(articles))
+
+	for i, a := range articles {
+		results[i].ID = &articles[i].ID
+
+		if seen[a.ID] {
+			results.fail(i, echo.NewHTTPError(
+				http.StatusBadRequest,
+				"article is already updated by an earlier item",
+			))
+			continue
+		}
+		seen[a.ID] = true
+
+		if err := a.Validate(); err != nil {
+			results.fail(i, echo.NewHTTPError(http.StatusBadRequest, err.Error()))
+			continue
+		}
+
+		ids = append(ids, a.ID)
+	}
+
+	if len(ids) == 0 {
+		return results, nil
+	}
+
+	var (
+		updated []*models.Article
+		index   []int
+	)
+
+	if err := u.uow.Do(nil, func(r *repositories.TxRepositories) error {
+		current, err := r.Articles.GetForUpdate(ids)
+		if err != nil {
+			u.log.Errorf("article.pgRepository.GetForUpdate: %v", err)
+			return err
+		}
+
+		byID := make(map[uuid.UUID]*models.Article, len(current))
+		for i := range current {
+			byID[current[i].ID] = &current[i]
+		}
+
+		now := time.Now()
+
+		for i, a := range articles {
+			if results.failed(i) {
+				continue
+			}
+
+			c, ok := byID[a.ID]
+			if !ok || c.AuthorID != a.AuthorID {
+				results.fail(i, echo.ErrNotFound)
+				continue
+			}
+
+			if !a.UpdatedAt.IsZero() && !a.UpdatedAt.Equal(c.UpdatedAt) {
+				results.fail(i, repositories.ErrArticleConflict)
+				continue
+			}
+
+			if err := prepareUpdate(a, c, now); err != nil {
+				results.fail(i, err)
+				continue
+			}
+
+			res, err := r.Articles.Update(a)
+			if err != nil {
+				u.log.Errorf("article.pgRepository.Update: %v", err)
+				return err
+			}
+
+			if err := u.emitUpdated(r, res, c.Status); err != nil {
+				return err
+			}
+
+			updated = append(updated, res)
+			index = append(index, i)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	for j, res := range updated {
+		results.succeed(index[j], http.StatusOK, res)
+	}
+
+	u.cacheMany(updated)
+
+	return results, nil
+}
+
+func (u *usecase) DeleteMany(
+	ids []uuid.UUID,
+	authorID uuid.UUID,
+) ([]models.ArticleBulkResult, error) {
+	results := newBulkResults(len(ids))
+
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+
+	for i, id := range ids {
+		results[i].ID = &ids[i]
+
+		if seen[id] {
+			results.fail(i, echo.NewHTTPError(
+				http.StatusBadRequest,
+				"article is already deleted by an earlier item",
+			))
+			continue
+		}
+		seen[id] = true
+
+		unique = append(unique, id)
+	}
+
+	if len(unique) == 0 {
+		return results, nil
+	}
+
+	deleted := make(map[uuid.UUID]bool, len(unique))
+
+	if err := u.uow.Do(nil, func(r *repositories.TxRepositories) error {
+		current, err := r.Articles.GetForUpdate(unique)
+		if err != nil {
+			u.log.Errorf("article.pgRepository.GetForUpdate: %v", err)
+			return err
+		}
+
+		byID := make(map[uuid.UUID]*models.Article, len(current))
+		own := make([]uuid.UUID, 0, len(current))
+		for i := range current {
+			if current[i].AuthorID == authorID {
+				byID[current[i].ID] = &current[i]
+				own = append(own, current[i].ID)
+			}
+		}
+
+		if len(own) == 0 {
+			return nil
+		}
+
+		res, err := r.Articles.DeleteMany(own, authorID)
+		if err != nil {
+			u.log.Errorf("article.pgRepository.DeleteMany: %v", err)
+			return err
+		}
+
+		for _, id := range res {
+			c := byID[id]
+
+			if err := u.emit(
+				r,
+				models.EventArticleDeleted,
+				id,
+				&models.ArticleDeletedPayload{
+					ID:       id,
+					AuthorID: authorID,
+					Status:   c.Status,
+					Tags:     c.Tags,
+				},
+			); err != nil {
+				return err
+			}
+
+			deleted[id] = true
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	removed := make([]uuid.UUID, 0, len(deleted))
+	for i, id := range ids {
+		switch {
+		case results.failed(i):
+		case deleted[id]:
+			results[i].Status = http.StatusNoContent
+			removed = append(removed, id)
+		default:
+			results.fail(i, echo.ErrNotFound)
+		}
+	}
+
+	if err := u.redisRepository.DeleteMany(removed); err != nil {
+		u.log.Errorf("article.redisRepository.DeleteMany: %v", err)
+	}
+
+	return results, nil
+}
+
+// cacheMany renders the descriptions of freshly written articles and
+// caches them in one round trip. The articles are committed by then, so
+// a failure is only logged.
+func (u *usecase) cacheMany(articles []*models.Article) {
+	if len(articles) == 0 {
+		return
+	}
+
+	for _, a := range articles {
+		if err := renderDesc(a); err != nil {
+			u.log.Errorf("article.renderDesc: %v", err)
+		}
+	}
+
+	if err := u.redisRepository.SetArticles(
+		articles,
+		time.Second*cacheDuration,
+	); err != nil {
+		u.log.Errorf("article.redisRepository.SetArticles: %v", err)
+	}
+}
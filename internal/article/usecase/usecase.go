@@ -10,11 +10,14 @@ import (
 	"github.com/slavtov/clean-architecture/internal/domain/repositories"
 	"github.com/slavtov/clean-architecture/internal/domain/usecases"
 	"github.com/slavtov/clean-architecture/pkg/logger"
+	"github.com/slavtov/clean-architecture/pkg/markdown"
+	"github.com/slavtov/clean-architecture/pkg/slug"
 )
 
 type usecase struct {
 	pgRepository    repositories.PGArticleRepository
 	redisRepository repositories.RedisArticleRepository
+	uow             repositories.UnitOfWork
 	log             logger.Logger
 }
 
@@ -23,32 +26,101 @@ const cacheDuration = 3600
 func New(
 	pg repositories.PGArticleRepository,
 	redis repositories.RedisArticleRepository,
+	uow repositories.UnitOfWork,
 	log logger.Logger,
 ) usecases.ArticleUseCase {
 	return &usecase{
 		pgRepository:    pg,
 		redisRepository: redis,
+		uow:             uow,
 		log:             log,
 	}
 }
 
-func (u *usecase) GetAll() ([]models.Article, error) {
-	res, err := u.pgRepository.GetAll()
+func (u *usecase) GetAll(
+	filter *models.ArticleFilter,
+) ([]models.Article, error) {
+	res, err := u.pgRepository.GetAll(filter)
 	if err != nil {
 		u.log.Errorf("article.pgRepository.GetAll: %v", err)
 		return res, err
 	}
 
+	u.renderAll(res)
+	u.setReactions(res, filter.ViewerID)
+
+	return res, nil
+}
+
+func (u *usecase) GetByID(
+	id uuid.UUID,
+	viewerID uuid.UUID,
+) (models.Article, error) {
+	res, err := u.getVisible(id, viewerID)
+	if err != nil {
+		return res, err
+	}
+
+	articles := []models.Article{res}
+	u.setReactions(articles, viewerID)
+
+	return articles[0], nil
+}
+
+func (u *usecase) GetBySlug(
+	slug string,
+	viewerID uuid.UUID,
+) (models.Article, error) {
+	id, err := u.redisRepository.GetIDBySlug(slug)
+	if err != nil {
+		u.log.Errorf("article.redisRepository.GetIDBySlug: %v", err)
+	}
+
+	if id == uuid.Nil {
+		if id, err = u.pgRepository.GetIDBySlug(slug); err != nil {
+			u.log.Errorf("article.pgRepository.GetIDBySlug: %v", err)
+			return models.Article{}, err
+		}
+
+		if err := u.redisRepository.SetSlug(
+			slug,
+			id,
+			time.Second*cacheDuration,
+		); err != nil {
+			u.log.Errorf("article.redisRepository.SetSlug: %v", err)
+		}
+	}
+
+	return u.GetByID(id, viewerID)
+}
+
+func (u *usecase) getVisible(
+	id uuid.UUID,
+	viewerID uuid.UUID,
+) (models.Article, error) {
+	res, err := u.getByID(id)
+	if err != nil {
+		return res, err
+	}
+
+	if !res.IsVisibleTo(viewerID) {
+		return models.Article{}, echo.ErrNotFound
+	}
+
 	return res, nil
 }
 
-func (u *usecase) GetByID(id uuid.UUID) (models.Article, error) {
+func (u *usecase) getByID(id uuid.UUID) (models.Article, error) {
 	cachedArticle, err := u.redisRepository.GetByID(id)
 	if err != nil {
 		u.log.Errorf("article.redisRepository.GetByID: %v", err)
 	}
 
 	if cachedArticle.ID != uuid.Nil {
+		if cachedArticle.DescHTML == "" {
+			u.render(&cachedArticle)
+		}
+
 		return cachedArticle, nil
 	}
 
@@ -58,6 +130,8 @@ func (u *usecase) GetByID(id uuid.UUID) (models.Article, error) {
 		return res, err
 	}
 
+	u.render(&res)
+
 	if err := u.redisRepository.SetArticle(
 		&res,
 		time.Second*cacheDuration,
@@ -70,16 +144,26 @@ func (u *usecase) GetByID(id uuid.UUID) (models.Article, error) {
 }
 
 func (u *usecase) Store(article *models.Article) (*models.Article, error) {
-	if err := article.Validate(); err != nil {
-		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	if err := prepareStore(article, time.Now()); err != nil {
+		return nil, err
 	}
 
-	res, err := u.pgRepository.Store(article)
-	if err != nil {
-		u.log.Errorf("article.pgRepository.Store: %v", err)
+	var res *models.Article
+
+	if err := u.uow.Do(nil, func(r *repositories.TxRepositories) error {
+		var err error
+		if res, err = r.Articles.Store(article); err != nil {
+			u.log.Errorf("article.pgRepository.Store: %v", err)
+			return err
+		}
+
+		return u.emit(r, models.EventArticleCreated, res.ID, res)
+	}); err != nil {
 		return nil, err
 	}
 
+	u.render(res)
+
 	if err := u.redisRepository.SetArticle(
 		res,
 		time.Second*cacheDuration,
@@ -91,17 +175,57 @@ func (u *usecase) Store(article *models.Article) (*models.Article, error) {
 	return res, nil
 }
 
+// prepareStore validates a new article and fills in what it derives from
+// the request.
+func prepareStore(article *models.Article, now time.Time) error {
+	if err := article.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := article.ApplyStatus(nil, now); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	article.Slug = slug.Make(article.Title)
+	if article.Format == "" {
+		article.Format = models.ArticlePlain
+	}
+
+	return nil
+}
+
 func (u *usecase) Update(article *models.Article) (*models.Article, error) {
 	if err := article.Validate(); err != nil {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	res, err := u.pgRepository.Update(article)
-	if err != nil {
-		u.log.Errorf("article.pgRepository.Update: %v", err)
+	var res *models.Article
+
+	// The status transition is checked against the state the update is
+	// applied to, so the article stays locked until the update is done.
+	if err := u.uow.Do(nil, func(r *repositories.TxRepositories) error {
+		current, err := r.Articles.GetByIDForUpdate(article.ID)
+		if err != nil {
+			u.log.Errorf("article.pgRepository.GetByIDForUpdate: %v", err)
+			return err
+		}
+
+		if err := prepareUpdate(article, &current, time.Now()); err != nil {
+			return err
+		}
+
+		if res, err = r.Articles.Update(article); err != nil {
+			u.log.Errorf("article.pgRepository.Update: %v", err)
+			return err
+		}
+
+		return u.emitUpdated(r, res, current.Status)
+	}); err != nil {
 		return nil, err
 	}
 
+	u.render(res)
+
 	if err := u.redisRepository.SetArticle(
 		res,
 		time.Second*cacheDuration,
@@ -113,9 +237,54 @@ func (u *usecase) Update(article *models.Article) (*models.Article, error) {
 	return res, nil
 }
 
+// prepareUpdate checks an update against the current state of the
+// article and fills in what it derives from the request.
+func prepareUpdate(
+	article *models.Article,
+	current *models.Article,
+	now time.Time,
+) error {
+	if current.AuthorID != article.AuthorID {
+		return echo.ErrNotFound
+	}
+
+	if err := article.ApplyStatus(current, now); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	article.Slug = ""
+	if article.Title != current.Title {
+		article.Slug = slug.Make(article.Title)
+	}
+
+	return nil
+}
+
 func (u *usecase) Delete(article models.Article) error {
-	if err := u.pgRepository.Delete(article); err != nil {
-		u.log.Errorf("article.pgRepository.Delete: %v", err)
+	if err := u.uow.Do(nil, func(r *repositories.TxRepositories) error {
+		current, err := r.Articles.GetByID(article.ID)
+		if err != nil {
+			u.log.Errorf("article.pgRepository.GetByID: %v", err)
+			return err
+		}
+
+		if err := r.Articles.Delete(article); err != nil {
+			u.log.Errorf("article.pgRepository.Delete: %v", err)
+			return err
+		}
+
+		return u.emit(
+			r,
+			models.EventArticleDeleted,
+			article.ID,
+			&models.ArticleDeletedPayload{
+				ID:       article.ID,
+				AuthorID: article.AuthorID,
+				Status:   current.Status,
+				Tags:     current.Tags,
+			},
+		)
+	}); err != nil {
 		return err
 	}
 
@@ -126,3 +295,450 @@ func (u *usecase) Delete(article models.Article) error {
 
 	return nil
 }
+
+func (u *usecase) Restore(article models.Article) (*models.Article, error) {
+	var res *models.Article
+
+	if err := u.uow.Do(nil, func(r *repositories.TxRepositories) error {
+		var err error
+		if res, err = r.Articles.Restore(article); err != nil {
+			u.log.Errorf("article.pgRepository.Restore: %v", err)
+			return err
+		}
+
+		// A deleted article is in no feed, whatever its status was.
+		return u.emitUpdated(r, res, "")
+	}); err != nil {
+		return nil, err
+	}
+
+	u.render(res)
+
+	if err := u.redisRepository.SetArticle(
+		res,
+		time.Second*cacheDuration,
+	); err != nil {
+		u.log.Errorf("article.redisRepository.SetArticle: %v", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (u *usecase) Purge(before time.Time) (int64, error) {
+	n, err := u.pgRepository.Purge(before)
+	if err != nil {
+		u.log.Errorf("article.pgRepository.Purge: %v", err)
+		return 0, err
+	}
+
+	if n > 0 {
+		u.log.Infof("article.Purge: %d articles purged", n)
+	}
+
+	return n, nil
+}
+
+func (u *usecase) PublishDue(limit int) (int, error) {
+	var res []models.Article
+
+	if err := u.uow.Do(nil, func(r *repositories.TxRepositories) error {
+		var err error
+		if res, err = r.Articles.PublishDue(limit); err != nil {
+			u.log.Errorf("article.pgRepository.PublishDue: %v", err)
+			return err
+		}
+
+		for i := range res {
+			if err := u.emitUpdated(
+				r,
+				&res[i],
+				models.ArticleScheduled,
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	u.renderAll(res)
+
+	for i := range res {
+		if err := u.redisRepository.SetArticle(
+			&res[i],
+			time.Second*cacheDuration,
+		); err != nil {
+			u.log.Errorf("article.redisRepository.SetArticle: %v", err)
+		}
+	}
+
+	if len(res) > 0 {
+		u.log.Infof("article.PublishDue: %d articles published", len(res))
+	}
+
+	return len(res), nil
+}
+
+func (u *usecase) GetRevisions(
+	id uuid.UUID,
+	viewerID uuid.UUID,
+) ([]models.ArticleRevision, error) {
+	if _, err := u.getVisible(id, viewerID); err != nil {
+		return nil, err
+	}
+
+	res, err := u.pgRepository.GetRevisions(id)
+	if err != nil {
+		u.log.Errorf("article.pgRepository.GetRevisions: %v", err)
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (u *usecase) GetRevision(
+	id uuid.UUID,
+	rev int,
+	viewerID uuid.UUID,
+) (models.ArticleRevision, error) {
+	if _, err := u.getVisible(id, viewerID); err != nil {
+		return models.ArticleRevision{}, err
+	}
+
+	res, err := u.pgRepository.GetRevision(id, rev)
+	if err != nil {
+		u.log.Errorf("article.pgRepository.GetRevision: %v", err)
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (u *usecase) DiffRevisions(
+	id uuid.UUID,
+	from int,
+	to int,
+	viewerID uuid.UUID,
+) (*models.ArticleRevisionDiff, error) {
+	fromRevision, err := u.GetRevision(id, from, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	toRevision, err := u.pgRepository.GetRevision(id, to)
+	if err != nil {
+		u.log.Errorf("article.pgRepository.GetRevision: %v", err)
+		return nil, err
+	}
+
+	return models.NewArticleRevisionDiff(&fromRevision, &toRevision), nil
+}
+
+func (u *usecase) RestoreRevision(
+	article models.Article,
+	rev int,
+) (*models.Article, error) {
+	revision, err := u.pgRepository.GetRevision(article.ID, rev)
+	if err != nil {
+		u.log.Errorf("article.pgRepository.GetRevision: %v", err)
+		return nil, err
+	}
+
+	article.Title = revision.Title
+	article.Desc = revision.Desc
+
+	return u.Update(&article)
+}
+
+func (u *usecase) GetTags() ([]models.Tag, error) {
+	res, err := u.pgRepository.GetTags()
+	if err != nil {
+		u.log.Errorf("article.pgRepository.GetTags: %v", err)
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (u *usecase) Like(id uuid.UUID, userID uuid.UUID) error {
+	if _, err := u.getByIDForReaction(id); err != nil {
+		return err
+	}
+
+	liked, err := u.pgRepository.Like(id, userID)
+	if err != nil {
+		u.log.Errorf("article.pgRepository.Like: %v", err)
+		return err
+	}
+
+	if !liked {
+		return nil
+	}
+
+	if err := u.redisRepository.IncrLikes(id, 1); err != nil {
+		u.log.Errorf("article.redisRepository.IncrLikes: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (u *usecase) Unlike(id uuid.UUID, userID uuid.UUID) error {
+	unliked, err := u.pgRepository.Unlike(id, userID)
+	if err != nil {
+		u.log.Errorf("article.pgRepository.Unlike: %v", err)
+		return err
+	}
+
+	if !unliked {
+		return nil
+	}
+
+	if err := u.redisRepository.IncrLikes(id, -1); err != nil {
+		u.log.Errorf("article.redisRepository.IncrLikes: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (u *usecase) Bookmark(id uuid.UUID, userID uuid.UUID) error {
+	if _, err := u.getByIDForReaction(id); err != nil {
+		return err
+	}
+
+	if err := u.pgRepository.Bookmark(id, userID); err != nil {
+		u.log.Errorf("article.pgRepository.Bookmark: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (u *usecase) Unbookmark(id uuid.UUID, userID uuid.UUID) error {
+	if err := u.pgRepository.Unbookmark(id, userID); err != nil {
+		u.log.Errorf("article.pgRepository.Unbookmark: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (u *usecase) GetBookmarks(userID uuid.UUID) ([]models.Article, error) {
+	res, err := u.pgRepository.GetBookmarks(userID)
+	if err != nil {
+		u.log.Errorf("article.pgRepository.GetBookmarks: %v", err)
+		return res, err
+	}
+
+	u.renderAll(res)
+	u.setReactions(res, userID)
+
+	return res, nil
+}
+
+// ReconcileLikes recounts likes_count in Postgres for the articles liked
+// or unliked since the last run and refreshes their cached copies.
+func (u *usecase) ReconcileLikes() (int, error) {
+	ids, err := u.redisRepository.TakeLikes()
+	if err != nil {
+		u.log.Errorf("article.redisRepository.TakeLikes: %v", err)
+		return 0, err
+	}
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	res, err := u.pgRepository.ReconcileLikes(ids)
+	if err != nil {
+		u.log.Errorf("article.pgRepository.ReconcileLikes: %v", err)
+		return 0, err
+	}
+
+	u.renderAll(res)
+
+	if err := u.redisRepository.ClearLikes(); err != nil {
+		u.log.Errorf("article.redisRepository.ClearLikes: %v", err)
+		return 0, err
+	}
+
+	for i := range res {
+		if res[i].DeletedAt != nil {
+			continue
+		}
+
+		if err := u.redisRepository.SetArticle(
+			&res[i],
+			time.Second*cacheDuration,
+		); err != nil {
+			u.log.Errorf("article.redisRepository.SetArticle: %v", err)
+		}
+	}
+
+	return len(res), nil
+}
+
+// emit writes an event about the article to the outbox, so it is only
+// published if the transaction commits.
+func (u *usecase) emit(
+	r *repositories.TxRepositories,
+	eventType string,
+	id uuid.UUID,
+	payload interface{},
+) error {
+	event, err := models.NewEvent(eventType, id, payload)
+	if err != nil {
+		u.log.Errorf("article.NewEvent: %v", err)
+		return echo.ErrInternalServerError
+	}
+
+	if _, err := r.Outbox.Store(event); err != nil {
+		u.log.Errorf("outbox.pgRepository.Store: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// emitUpdated writes the article.updated event of the article, which had
+// the previous status before the update.
+func (u *usecase) emitUpdated(
+	r *repositories.TxRepositories,
+	article *models.Article,
+	previous models.ArticleStatus,
+) error {
+	return u.emit(
+		r,
+		models.EventArticleUpdated,
+		article.ID,
+		&models.ArticleUpdatedPayload{
+			Article:        article,
+			PreviousStatus: previous,
+		},
+	)
+}
+
+// getByIDForReaction returns the article if it can be liked or bookmarked,
+// which only published articles can.
+func (u *usecase) getByIDForReaction(id uuid.UUID) (models.Article, error) {
+	res, err := u.getByID(id)
+	if err != nil {
+		return res, err
+	}
+
+	if res.Status != models.ArticlePublished {
+		return models.Article{}, echo.ErrNotFound
+	}
+
+	return res, nil
+}
+
+// render sets the HTML description of the articles, taking it from the
+// cache when that version of the article has been rendered before.
+func (u *usecase) render(articles ...*models.Article) {
+	cached, err := u.redisRepository.GetDescHTML(articles)
+	if err != nil {
+		u.log.Errorf("article.redisRepository.GetDescHTML: %v", err)
+	}
+
+	for i, a := range articles {
+		if i < len(cached) && cached[i] != "" {
+			a.DescHTML = cached[i]
+			continue
+		}
+
+		if err := renderDesc(a); err != nil {
+			u.log.Errorf("article.renderDesc: %v", err)
+			continue
+		}
+
+		if err := u.redisRepository.SetDescHTML(
+			a,
+			time.Second*cacheDuration,
+		); err != nil {
+			u.log.Errorf("article.redisRepository.SetDescHTML: %v", err)
+		}
+	}
+}
+
+// Render sets the HTML description of articles that did not come from
+// this use case, e.g. those carried by events.
+func (u *usecase) Render(articles ...*models.Article) {
+	u.render(articles...)
+}
+
+// renderDesc sets DescHTML to the sanitized HTML of the description.
+func renderDesc(a *models.Article) error {
+	if a.Format != models.ArticleMarkdown {
+		a.DescHTML = markdown.RenderPlain(a.Desc)
+		return nil
+	}
+
+	html, err := markdown.Render(a.Desc)
+	if err != nil {
+		return err
+	}
+
+	a.DescHTML = html
+
+	return nil
+}
+
+func (u *usecase) renderAll(articles []models.Article) {
+	ptrs := make([]*models.Article, 0, len(articles))
+	for i := range articles {
+		ptrs = append(ptrs, &articles[i])
+	}
+
+	u.render(ptrs...)
+}
+
+// setReactions adds the likes that are not reconciled yet to the counts
+// and, for an authenticated viewer, their own liked and bookmarked state.
+func (u *usecase) setReactions(articles []models.Article, viewerID uuid.UUID) {
+	if len(articles) == 0 {
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(articles))
+	for _, a := range articles {
+		ids = append(ids, a.ID)
+	}
+
+	likes, err := u.redisRepository.GetLikes(ids)
+	if err != nil {
+		u.log.Errorf("article.redisRepository.GetLikes: %v", err)
+	}
+
+	for i, n := range likes {
+		articles[i].LikesCount += int(n)
+		if articles[i].LikesCount < 0 {
+			articles[i].LikesCount = 0
+		}
+	}
+
+	if viewerID == uuid.Nil {
+		return
+	}
+
+	reactions, err := u.pgRepository.GetReactions(viewerID, ids)
+	if err != nil {
+		u.log.Errorf("article.pgRepository.GetReactions: %v", err)
+		return
+	}
+
+	byID := make(map[uuid.UUID]models.ArticleReactions, len(reactions))
+	for _, r := range reactions {
+		byID[r.ArticleID] = r
+	}
+
+	for i := range articles {
+		r := byID[articles[i].ID]
+		articles[i].Liked = &r.Liked
+		articles[i].Bookmarked = &r.Bookmarked
+	}
+}
@@ -0,0 +1,239 @@
+package http
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/config"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/internal/domain/usecases"
+	"github.com/slavtov/clean-architecture/internal/middleware"
+	"github.com/slavtov/clean-architecture/pkg/logger"
+	"github.com/slavtov/clean-architecture/pkg/utils"
+)
+
+type handler struct {
+	cfg               *config.Config
+	attachmentUseCase usecases.AttachmentUseCase
+	userUseCase       usecases.UserUseCase
+	log               logger.Logger
+}
+
+// multipartOverhead is allowed on top of the file size for the rest of a
+// multipart upload body.
+const multipartOverhead = 1 << 20
+
+const attachmentRoute = "attachments.get"
+
+func newHandler(
+	cfg *config.Config,
+	au usecases.AttachmentUseCase,
+	uu usecases.UserUseCase,
+	log logger.Logger,
+) *handler {
+	return &handler{
+		cfg:               cfg,
+		attachmentUseCase: au,
+		userUseCase:       uu,
+		log:               log,
+	}
+}
+
+func Init(
+	cfg *config.Config,
+	e *echo.Group,
+	au usecases.AttachmentUseCase,
+	uu usecases.UserUseCase,
+	log logger.Logger,
+) {
+	h := newHandler(cfg, au, uu, log)
+	auth := middleware.Auth(cfg, uu, log)
+	optionalAuth := middleware.OptionalAuth(cfg, uu, log)
+
+	e.GET("/articles/:id/attachments", h.GetAll, optionalAuth)
+	e.POST("/articles/:id/attachments", h.Store, auth)
+	e.GET("/attachments/:id", h.GetByID, optionalAuth).Name = attachmentRoute
+	e.DELETE("/attachments/:id", h.Delete, auth)
+}
+
+// GetAll godoc
+// @Tags Attachments
+// @Summary Get article attachments
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Success 200 {object} models.AttachmentsList
+// @Failure 400,404,500 {object} swagger.Error
+// @Router /articles/{id}/attachments [get]
+func (h *handler) GetAll(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	res, err := h.attachmentUseCase.GetAll(id, utils.GetCtxViewerID(c))
+	if err != nil {
+		h.log.Errorf("attachment.UseCase.GetAll: %v", err)
+		return err
+	}
+
+	for i := range res {
+		setURL(c, &res[i])
+	}
+
+	return c.JSON(http.StatusOK, &models.AttachmentsList{
+		TotalCount:  len(res),
+		Attachments: res,
+	})
+}
+
+// GetByID godoc
+// @Tags Attachments
+// @Summary Download attachment
+// @Description Supports range requests. Attachments of published articles are cacheable by anyone.
+// @Produce octet-stream
+// @Param id path string true "Attachment ID"
+// @Param Range header string false "Byte range"
+// @Param If-None-Match header string false "ETag of the cached file"
+// @Success 200 {file} file
+// @Success 206 {file} file
+// @Success 304
+// @Header 200,206,304 {string} ETag "File checksum"
+// @Header 200,206,304 {string} Cache-Control "Caching policy"
+// @Failure 400,404,416,500 {object} swagger.Error
+// @Router /attachments/{id} [get]
+func (h *handler) GetByID(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	attachment, body, err := h.attachmentUseCase.Open(id, utils.GetCtxViewerID(c))
+	if err != nil {
+		h.log.Errorf("attachment.UseCase.Open: %v", err)
+		return err
+	}
+	defer body.Close()
+
+	disposition := "attachment"
+	if attachment.IsImage() {
+		disposition = "inline"
+	}
+
+	cacheControl := "private, no-cache"
+	if attachment.Public {
+		cacheControl = h.cfg.Uploads.CacheControl
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, attachment.ContentType)
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType(
+		disposition,
+		map[string]string{"filename": attachment.Filename},
+	))
+	header.Set(echo.HeaderXContentTypeOptions, "nosniff")
+	header.Set(middleware.HeaderCacheControl, cacheControl)
+	header.Set(utils.HeaderETag, `"`+attachment.Checksum+`"`)
+
+	// ServeContent takes care of Range, If-Range and the conditional
+	// headers based on the ETag and modification time set above.
+	http.ServeContent(
+		c.Response(),
+		c.Request(),
+		attachment.Filename,
+		attachment.CreatedAt,
+		body,
+	)
+
+	return nil
+}
+
+// Store godoc
+// @Tags Attachments
+// @Summary Upload attachment
+// @Description Only the author of the article can upload. The file type is detected from its contents.
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Article ID"
+// @Param file formData file true "File"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.Attachment
+// @Failure 400,401,404,413,415,500 {object} swagger.Error
+// @Router /articles/{id}/attachments [post]
+func (h *handler) Store(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	limit := h.cfg.Uploads.MaxSize + multipartOverhead
+	if c.Request().ContentLength > limit {
+		return repositories.ErrFileTooLarge
+	}
+
+	c.Request().Body = http.MaxBytesReader(
+		c.Response(),
+		c.Request().Body,
+		limit,
+	)
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return echo.ErrBadRequest
+	}
+
+	file, err := fh.Open()
+	if err != nil {
+		return echo.ErrBadRequest
+	}
+	defer file.Close()
+
+	createdAttachment, err := h.attachmentUseCase.Store(&models.Attachment{
+		ArticleID:  id,
+		UploaderID: utils.GetCtxID(c),
+		Filename:   fh.Filename,
+		Size:       fh.Size,
+	}, file)
+	if err != nil {
+		h.log.Errorf("attachment.UseCase.Store: %v", err)
+		return err
+	}
+
+	setURL(c, createdAttachment)
+
+	return c.JSON(http.StatusCreated, createdAttachment)
+}
+
+// Delete godoc
+// @Tags Attachments
+// @Summary Delete attachment
+// @Accept json
+// @Produce json
+// @Param id path string true "Attachment ID"
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 400,401,404,500 {object} swagger.Error
+// @Router /attachments/{id} [delete]
+func (h *handler) Delete(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	if err := h.attachmentUseCase.Delete(models.Attachment{
+		ID:         id,
+		UploaderID: utils.GetCtxID(c),
+	}); err != nil {
+		h.log.Errorf("attachment.UseCase.Delete: %v", err)
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func setURL(c echo.Context, attachment *models.Attachment) {
+	attachment.URL = c.Echo().Reverse(attachmentRoute, attachment.ID)
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+)
+
+// testBlobStore runs the behaviour every BlobStore must share against
+// store.
+func testBlobStore(t *testing.T, store repositories.BlobStore) {
+	t.Helper()
+
+	key := "articles/1/hello.txt"
+	content := []byte("hello, world")
+
+	if err := store.Put(
+		key,
+		bytes.NewReader(content),
+		int64(len(content)),
+		"text/plain",
+	); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	obj, err := store.Get(key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	got, err := io.ReadAll(obj)
+	obj.Close()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	if !bytes.Equal(got, content) {
+		t.Fatalf("Get = %q, want %q", got, content)
+	}
+
+	if err := store.Delete(key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if _, err := store.Get(key); httpCode(err) != http.StatusNotFound {
+		t.Fatalf("Get after Delete: err = %v, want 404", err)
+	}
+
+	if err := store.Delete(key); err != nil {
+		t.Fatalf("Delete of a missing key: %v", err)
+	}
+}
+
+func httpCode(err error) int {
+	if he, ok := err.(*echo.HTTPError); ok {
+		return he.Code
+	}
+
+	return 0
+}
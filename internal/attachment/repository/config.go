@@ -0,0 +1,21 @@
+package repository
+
+var (
+	getAttachmentQuery  = `SELECT * FROM attachments WHERE id = $1`
+	getAttachmentsQuery = `SELECT * FROM attachments 
+								WHERE article_id = $1 ORDER BY created_at`
+	createAttachmentQuery = `INSERT INTO attachments 
+								(id, article_id, uploader_id, key, filename, content_type, 
+								size, width, height, checksum) 
+								VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) 
+								RETURNING *`
+	deleteAttachmentQuery = `DELETE FROM attachments WHERE id = $1`
+	purgeAttachmentsQuery = `DELETE FROM attachments 
+								WHERE article_id IN (
+									SELECT a.id FROM articles a 
+									LEFT JOIN users u ON u.id = a.author_id 
+										AND u.deleted_at = a.deleted_at 
+									WHERE a.deleted_at < $1 
+									AND (u.id IS NULL OR u.erase_at < now())
+								) RETURNING key`
+)
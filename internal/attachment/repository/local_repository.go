@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+)
+
+type localRepository struct {
+	dir string
+}
+
+// NewLocalRepository stores blobs as files under dir, one per key.
+func NewLocalRepository(dir string) repositories.BlobStore {
+	return &localRepository{dir}
+}
+
+func (r *localRepository) Put(
+	key string,
+	src io.Reader,
+	size int64,
+	contentType string,
+) error {
+	name, err := r.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	// Write to a temporary file first, so readers never see a partial one.
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.CopyN(tmp, src, size); err != nil {
+		tmp.Close()
+		return echo.ErrInternalServerError
+	}
+
+	if err := tmp.Close(); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (r *localRepository) Get(key string) (io.ReadSeekCloser, error) {
+	name, err := r.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, echo.ErrNotFound
+		}
+
+		return nil, echo.ErrInternalServerError
+	}
+
+	return f, nil
+}
+
+func (r *localRepository) Delete(key string) error {
+	name, err := r.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// path maps a key to a file inside dir, rejecting keys that would escape it.
+func (r *localRepository) path(key string) (string, error) {
+	name := filepath.Join(r.dir, filepath.FromSlash(key))
+	if !strings.HasPrefix(name, filepath.Clean(r.dir)+string(filepath.Separator)) {
+		return "", echo.ErrBadRequest
+	}
+
+	return name, nil
+}
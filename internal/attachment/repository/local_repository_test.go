@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalRepository(t *testing.T) {
+	testBlobStore(t, NewLocalRepository(t.TempDir()))
+}
+
+func TestLocalRepositoryPutIsAtomic(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocalRepository(dir)
+
+	// A short read fails the upload and must not leave a file behind.
+	if err := store.Put("a/b.txt", strings.NewReader("abc"), 10, ""); err == nil {
+		t.Fatal("Put with a short body succeeded")
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 0 {
+		t.Fatalf("files left behind: %v", entries)
+	}
+}
+
+func TestLocalRepositoryRejectsEscapingKeys(t *testing.T) {
+	store := NewLocalRepository(t.TempDir())
+
+	for _, key := range []string{"../outside", "a/../../outside", ""} {
+		if err := store.Put(
+			key,
+			strings.NewReader("x"),
+			1,
+			"",
+		); httpCode(err) != http.StatusBadRequest {
+			t.Errorf("Put(%q): err = %v, want 400", key, err)
+		}
+
+		if _, err := store.Get(key); httpCode(err) != http.StatusBadRequest {
+			t.Errorf("Get(%q): err = %v, want 400", key, err)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/pkg/store/postgres"
+)
+
+type pgRepository struct {
+	db postgres.DB
+}
+
+func NewPGRepository(db postgres.DB) repositories.PGAttachmentRepository {
+	return &pgRepository{db}
+}
+
+func (r *pgRepository) GetAll(articleID uuid.UUID) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+
+	if err := r.db.Select(
+		&attachments,
+		getAttachmentsQuery,
+		articleID,
+	); err != nil {
+		return attachments, echo.ErrInternalServerError
+	}
+
+	return attachments, nil
+}
+
+func (r *pgRepository) GetByID(id uuid.UUID) (models.Attachment, error) {
+	var attachment models.Attachment
+
+	if err := r.db.Get(
+		&attachment,
+		getAttachmentQuery,
+		id,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return attachment, echo.ErrNotFound
+		}
+
+		return attachment, echo.ErrBadRequest
+	}
+
+	return attachment, nil
+}
+
+func (r *pgRepository) Store(a *models.Attachment) (*models.Attachment, error) {
+	var attachment models.Attachment
+
+	if err := r.db.QueryRowx(
+		createAttachmentQuery,
+		a.ID,
+		a.ArticleID,
+		a.UploaderID,
+		a.Key,
+		a.Filename,
+		a.ContentType,
+		a.Size,
+		a.Width,
+		a.Height,
+		a.Checksum,
+	).StructScan(&attachment); err != nil {
+		return nil, echo.ErrBadRequest
+	}
+
+	return &attachment, nil
+}
+
+func (r *pgRepository) Delete(id uuid.UUID) error {
+	res, err := r.db.Exec(deleteAttachmentQuery, id)
+	if err != nil {
+		return echo.ErrBadRequest
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	if rowsAffected == 0 {
+		return echo.ErrNotFound
+	}
+
+	return nil
+}
+
+// Purge removes the attachments of purged articles and returns the keys
+// of their blobs. It has to run before the articles are purged.
+func (r *pgRepository) Purge(before time.Time) ([]string, error) {
+	var keys []string
+
+	if err := r.db.Select(
+		&keys,
+		purgeAttachmentsQuery,
+		before,
+	); err != nil {
+		return keys, echo.ErrInternalServerError
+	}
+
+	return keys, nil
+}
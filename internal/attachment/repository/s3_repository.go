@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"io"
+
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/pkg/store/s3"
+)
+
+type s3Repository struct {
+	s3 s3.Store
+}
+
+func NewS3Repository(store s3.Store) repositories.BlobStore {
+	return &s3Repository{store}
+}
+
+func (r *s3Repository) Put(
+	key string,
+	src io.Reader,
+	size int64,
+	contentType string,
+) error {
+	if err := r.s3.Put(key, src, size, contentType); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (r *s3Repository) Get(key string) (io.ReadSeekCloser, error) {
+	obj, err := r.s3.Get(key)
+	if err != nil {
+		if err == s3.ErrNotFound {
+			return nil, echo.ErrNotFound
+		}
+
+		return nil, echo.ErrInternalServerError
+	}
+
+	return obj, nil
+}
+
+func (r *s3Repository) Delete(key string) error {
+	if err := r.s3.Del(key); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+//go:build s3
+// +build s3
+
+package repository
+
+import (
+	"os"
+	"testing"
+
+	"github.com/slavtov/clean-architecture/pkg/logger"
+	"github.com/slavtov/clean-architecture/pkg/store/s3"
+)
+
+// TestS3Repository runs against an S3 compatible server, e.g. the MinIO
+// container of docker-compose.local.yml:
+//
+//	make local
+//	go test -tags s3 ./internal/attachment/repository
+//
+// S3_ENDPOINT, S3_ACCESS_KEY, S3_SECRET_KEY and S3_BUCKET override the
+// defaults matching that container.
+func TestS3Repository(t *testing.T) {
+	store := s3.New(&s3.Config{
+		Endpoint:  getenv("S3_ENDPOINT", "localhost:9000"),
+		AccessKey: getenv("S3_ACCESS_KEY", "minioadmin"),
+		SecretKey: getenv("S3_SECRET_KEY", "minioadmin"),
+		Bucket:    getenv("S3_BUCKET", "attachments-test"),
+		Region:    "us-east-1",
+	}, logger.New())
+
+	if err := store.Open(); err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer store.Close()
+
+	testBlobStore(t, NewS3Repository(store))
+}
+
+func getenv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+
+	return fallback
+}
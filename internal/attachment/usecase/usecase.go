@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/config"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/internal/domain/usecases"
+	"github.com/slavtov/clean-architecture/pkg/logger"
+	_ "golang.org/x/image/webp"
+)
+
+type usecase struct {
+	cfg            *config.Config
+	pgRepository   repositories.PGAttachmentRepository
+	blobStore      repositories.BlobStore
+	articleUseCase usecases.ArticleUseCase
+	log            logger.Logger
+}
+
+// sniffLen is the most http.DetectContentType looks at.
+const sniffLen = 512
+
+func New(
+	cfg *config.Config,
+	pg repositories.PGAttachmentRepository,
+	blob repositories.BlobStore,
+	au usecases.ArticleUseCase,
+	log logger.Logger,
+) usecases.AttachmentUseCase {
+	return &usecase{
+		cfg:            cfg,
+		pgRepository:   pg,
+		blobStore:      blob,
+		articleUseCase: au,
+		log:            log,
+	}
+}
+
+func (u *usecase) GetAll(
+	articleID uuid.UUID,
+	viewerID uuid.UUID,
+) ([]models.Attachment, error) {
+	if _, err := u.articleUseCase.GetByID(articleID, viewerID); err != nil {
+		return nil, err
+	}
+
+	res, err := u.pgRepository.GetAll(articleID)
+	if err != nil {
+		u.log.Errorf("attachment.pgRepository.GetAll: %v", err)
+		return res, err
+	}
+
+	return res, nil
+}
+
+// Open returns the attachment together with its contents, which the
+// caller has to close.
+func (u *usecase) Open(
+	id uuid.UUID,
+	viewerID uuid.UUID,
+) (models.Attachment, io.ReadSeekCloser, error) {
+	res, err := u.pgRepository.GetByID(id)
+	if err != nil {
+		u.log.Errorf("attachment.pgRepository.GetByID: %v", err)
+		return res, nil, err
+	}
+
+	article, err := u.articleUseCase.GetByID(res.ArticleID, viewerID)
+	if err != nil {
+		return res, nil, err
+	}
+
+	res.Public = article.Status == models.ArticlePublished
+
+	body, err := u.blobStore.Get(res.Key)
+	if err != nil {
+		u.log.Errorf("attachment.blobStore.Get: %v", err)
+		return res, nil, err
+	}
+
+	return res, body, nil
+}
+
+func (u *usecase) Store(
+	attachment *models.Attachment,
+	file io.ReadSeeker,
+) (*models.Attachment, error) {
+	if err := attachment.Validate(); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	article, err := u.articleUseCase.GetByID(
+		attachment.ArticleID,
+		attachment.UploaderID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if article.AuthorID != attachment.UploaderID {
+		return nil, echo.ErrNotFound
+	}
+
+	if attachment.Size > u.cfg.Uploads.MaxSize {
+		return nil, repositories.ErrFileTooLarge
+	}
+
+	if err := u.inspect(attachment, file); err != nil {
+		return nil, err
+	}
+
+	attachment.ID = uuid.New()
+	attachment.Key = fmt.Sprintf(
+		"articles/%s/%s",
+		attachment.ArticleID,
+		attachment.ID,
+	)
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, echo.ErrInternalServerError
+	}
+
+	if err := u.blobStore.Put(
+		attachment.Key,
+		file,
+		attachment.Size,
+		attachment.ContentType,
+	); err != nil {
+		u.log.Errorf("attachment.blobStore.Put: %v", err)
+		return nil, err
+	}
+
+	res, err := u.pgRepository.Store(attachment)
+	if err != nil {
+		u.log.Errorf("attachment.pgRepository.Store: %v", err)
+
+		if err := u.blobStore.Delete(attachment.Key); err != nil {
+			u.log.Errorf("attachment.blobStore.Delete: %v", err)
+		}
+
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (u *usecase) Delete(attachment models.Attachment) error {
+	current, err := u.pgRepository.GetByID(attachment.ID)
+	if err != nil {
+		u.log.Errorf("attachment.pgRepository.GetByID: %v", err)
+		return err
+	}
+
+	article, err := u.articleUseCase.GetByID(
+		current.ArticleID,
+		attachment.UploaderID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if article.AuthorID != attachment.UploaderID {
+		return echo.ErrNotFound
+	}
+
+	if err := u.pgRepository.Delete(current.ID); err != nil {
+		u.log.Errorf("attachment.pgRepository.Delete: %v", err)
+		return err
+	}
+
+	if err := u.blobStore.Delete(current.Key); err != nil {
+		u.log.Errorf("attachment.blobStore.Delete: %v", err)
+	}
+
+	return nil
+}
+
+func (u *usecase) Purge(before time.Time) (int64, error) {
+	keys, err := u.pgRepository.Purge(before)
+	if err != nil {
+		u.log.Errorf("attachment.pgRepository.Purge: %v", err)
+		return 0, err
+	}
+
+	for _, key := range keys {
+		if err := u.blobStore.Delete(key); err != nil {
+			u.log.Errorf("attachment.blobStore.Delete: %v", err)
+		}
+	}
+
+	if len(keys) > 0 {
+		u.log.Infof("attachment.Purge: %d attachments purged", len(keys))
+	}
+
+	return int64(len(keys)), nil
+}
+
+// inspect fills in what is known about the file from its contents rather
+// than from what the client claims: the type, the size, the checksum and
+// the dimensions of images.
+func (u *usecase) inspect(attachment *models.Attachment, file io.ReadSeeker) error {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return echo.ErrBadRequest
+	}
+
+	attachment.ContentType = http.DetectContentType(head[:n])
+	if !u.isAllowed(attachment.ContentType) {
+		return echo.NewHTTPError(
+			http.StatusUnsupportedMediaType,
+			fmt.Sprintf("%s files are not allowed", attachment.ContentType),
+		)
+	}
+
+	if attachment.IsImage() {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return echo.ErrInternalServerError
+		}
+
+		img, _, err := image.DecodeConfig(file)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid image")
+		}
+
+		if img.Width > u.cfg.Uploads.MaxWidth ||
+			img.Height > u.cfg.Uploads.MaxHeight {
+			return echo.NewHTTPError(
+				http.StatusBadRequest,
+				fmt.Sprintf(
+					"image must not exceed %dx%d",
+					u.cfg.Uploads.MaxWidth,
+					u.cfg.Uploads.MaxHeight,
+				),
+			)
+		}
+
+		attachment.Width = &img.Width
+		attachment.Height = &img.Height
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, io.LimitReader(file, u.cfg.Uploads.MaxSize+1))
+	if err != nil {
+		return echo.ErrBadRequest
+	}
+
+	if size > u.cfg.Uploads.MaxSize {
+		return repositories.ErrFileTooLarge
+	}
+
+	attachment.Size = size
+	attachment.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	return nil
+}
+
+func (u *usecase) isAllowed(contentType string) bool {
+	for _, t := range u.cfg.Uploads.AllowedTypes {
+		if t == contentType {
+			return true
+		}
+	}
+
+	return false
+}
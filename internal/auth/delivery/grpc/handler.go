@@ -0,0 +1,270 @@
+package grpc
+
+import (
+	"context"
+	"net"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/config"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/usecases"
+	apiv1 "github.com/slavtov/clean-architecture/pkg/api/v1"
+	"github.com/slavtov/clean-architecture/pkg/logger"
+	"github.com/slavtov/clean-architecture/pkg/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// OptionalAuthMethods may be called anonymously.
+var OptionalAuthMethods = []string{
+	"/api.v1.AuthService/Register",
+	"/api.v1.AuthService/Login",
+	"/api.v1.AuthService/Refresh",
+	"/api.v1.AuthService/GetUser",
+}
+
+type handler struct {
+	apiv1.UnimplementedAuthServiceServer
+	cfg         *config.Config
+	userUseCase usecases.UserUseCase
+	log         logger.Logger
+}
+
+func newHandler(
+	cfg *config.Config,
+	uu usecases.UserUseCase,
+	log logger.Logger,
+) *handler {
+	return &handler{
+		cfg:         cfg,
+		userUseCase: uu,
+		log:         log,
+	}
+}
+
+func Init(
+	cfg *config.Config,
+	s grpc.ServiceRegistrar,
+	uu usecases.UserUseCase,
+	log logger.Logger,
+) {
+	apiv1.RegisterAuthServiceServer(s, newHandler(cfg, uu, log))
+}
+
+func (h *handler) Register(
+	ctx context.Context,
+	req *apiv1.RegisterRequest,
+) (*apiv1.AuthResponse, error) {
+	displayName := req.GetDisplayName()
+
+	createdUser, err := h.userUseCase.Store(&models.User{
+		Email:       req.GetEmail(),
+		Password:    req.GetPassword(),
+		Handle:      req.GetHandle(),
+		DisplayName: &displayName,
+	})
+	if err != nil {
+		h.log.Errorf("auth.UseCase.Store: %v", err)
+		return nil, err
+	}
+
+	h.audit(ctx, createdUser.User.ID, models.AuditRegister)
+
+	return authResponse(createdUser), nil
+}
+
+func (h *handler) Login(
+	ctx context.Context,
+	req *apiv1.LoginRequest,
+) (*apiv1.AuthResponse, error) {
+	user, err := h.userUseCase.Login(&models.User{
+		Email:    req.GetEmail(),
+		Password: req.GetPassword(),
+	})
+	if err != nil {
+		h.log.Errorf("auth.UseCase.Login: %v", err)
+		return nil, err
+	}
+
+	if user.Restored {
+		h.audit(ctx, user.User.ID, models.AuditDeletionCancelled)
+	}
+
+	h.audit(ctx, user.User.ID, models.AuditLogin)
+
+	return authResponse(user), nil
+}
+
+func (h *handler) Refresh(
+	ctx context.Context,
+	req *apiv1.RefreshRequest,
+) (*apiv1.AuthResponse, error) {
+	refreshID, userID, err := utils.ParseToken(
+		"refresh",
+		req.GetRefreshToken(),
+		h.cfg.Server.JwtRefreshSecret,
+	)
+	if err != nil {
+		h.log.Errorf("verifyRefreshToken: %v", err)
+		return nil, echo.ErrUnauthorized
+	}
+
+	if _, err := h.userUseCase.GetToken(
+		userID,
+		refreshID,
+	); err != nil {
+		h.log.Errorf("auth.UseCase.GetToken: %v", err)
+		return nil, echo.ErrUnauthorized
+	}
+
+	u, err := h.userUseCase.GetByID(userID)
+	if err != nil {
+		h.log.Errorf("auth.UseCase.GetByID: %v", err)
+		return nil, err
+	}
+
+	user, err := h.userUseCase.Auth(&u)
+	if err != nil {
+		h.log.Errorf("auth.UseCase.Auth: %v", err)
+		return nil, err
+	}
+
+	if err := h.userUseCase.DeleteToken(
+		userID,
+		refreshID,
+	); err != nil {
+		h.log.Errorf("auth.UseCase.DeleteToken: %v", err)
+		return nil, err
+	}
+
+	return authResponse(user), nil
+}
+
+func (h *handler) Logout(
+	ctx context.Context,
+	_ *emptypb.Empty,
+) (*emptypb.Empty, error) {
+	userID := utils.GetGrpcViewerID(ctx)
+
+	if err := h.userUseCase.Logout(
+		userID,
+		utils.GetGrpcTokens(ctx),
+	); err != nil {
+		h.log.Errorf("auth.UseCase.Logout: %v", err)
+		return nil, err
+	}
+
+	h.audit(ctx, userID, models.AuditLogout)
+
+	return &emptypb.Empty{}, nil
+}
+
+func (h *handler) Me(
+	ctx context.Context,
+	_ *emptypb.Empty,
+) (*apiv1.User, error) {
+	res, err := h.userUseCase.GetByID(utils.GetGrpcViewerID(ctx))
+	if err != nil {
+		h.log.Errorf("auth.UseCase.GetByID: %v", err)
+		return nil, err
+	}
+
+	return userResponse(&res), nil
+}
+
+func (h *handler) GetUser(
+	ctx context.Context,
+	req *apiv1.GetUserRequest,
+) (*apiv1.Profile, error) {
+	var (
+		user models.User
+		err  error
+	)
+
+	if id, parseErr := uuid.Parse(req.GetId()); parseErr == nil {
+		user, err = h.userUseCase.GetByID(id)
+	} else {
+		user, err = h.userUseCase.GetByHandle(req.GetId())
+	}
+
+	if err != nil {
+		h.log.Errorf("auth.UseCase.GetByID: %v", err)
+		return nil, err
+	}
+
+	p := user.Profile()
+
+	return &apiv1.Profile{
+		Id:          p.ID.String(),
+		Handle:      p.Handle,
+		DisplayName: p.DisplayName,
+		Bio:         p.Bio,
+		AvatarUrl:   p.AvatarURL,
+		CreatedAt:   timestamppb.New(p.CreatedAt),
+	}, nil
+}
+
+// audit records an account event. A failure is logged but does not fail
+// the call, the event itself has already happened.
+func (h *handler) audit(ctx context.Context, userID uuid.UUID, action string) {
+	if err := h.userUseCase.Audit(auditEntry(ctx, userID, action)); err != nil {
+		h.log.Errorf("auth.UseCase.Audit: %v", err)
+	}
+}
+
+func auditEntry(
+	ctx context.Context,
+	userID uuid.UUID,
+	action string,
+) *models.AuditEntry {
+	entry := &models.AuditEntry{
+		UserID: userID,
+		Action: action,
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		entry.IP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(entry.IP); err == nil {
+			entry.IP = host
+		}
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ua := md.Get("user-agent"); len(ua) > 0 {
+			entry.UserAgent = utils.Truncate(ua[0], 500)
+		}
+	}
+
+	return entry
+}
+
+func authResponse(user *models.AuthUser) *apiv1.AuthResponse {
+	return &apiv1.AuthResponse{
+		User:         userResponse(user.User),
+		TokenType:    user.TokenType,
+		ExpiresIn:    int32(user.ExpiresIn),
+		AccessToken:  user.AccessToken,
+		RefreshToken: user.RefreshToken,
+		Restored:     user.Restored,
+	}
+}
+
+func userResponse(u *models.User) *apiv1.User {
+	p := u.Profile()
+
+	return &apiv1.User{
+		Id:          u.ID.String(),
+		Email:       u.Email,
+		Role:        u.Role,
+		Handle:      u.Handle,
+		DisplayName: p.DisplayName,
+		Bio:         p.Bio,
+		AvatarUrl:   p.AvatarURL,
+		UpdatedAt:   timestamppb.New(u.UpdatedAt),
+		CreatedAt:   timestamppb.New(u.CreatedAt),
+	}
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+)
+
+// zipExport packs each part of the export into its own JSON file.
+func zipExport(export *models.UserExport) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"articles.json", export.Articles},
+		{"sessions.json", export.Sessions},
+		{"audit.json", export.Audit},
+	}
+
+	for _, file := range files {
+		f, err := w.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+
+		if err := enc.Encode(file.data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -1,12 +1,14 @@
 package http
 
 import (
+	"mime"
 	"net/http"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/slavtov/clean-architecture/internal/config"
 	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
 	"github.com/slavtov/clean-architecture/internal/domain/usecases"
 	"github.com/slavtov/clean-architecture/internal/middleware"
 	"github.com/slavtov/clean-architecture/pkg/logger"
@@ -41,11 +43,14 @@ func Init(
 	authGroup.POST("/me", h.Me, auth)
 	authGroup.POST("/login", h.Login)
 	authGroup.POST("/register", h.Register)
+	authGroup.POST("/restore", h.Restore)
+	authGroup.GET("/email/confirm", h.ConfirmEmail)
 	authGroup.POST("/refresh", h.Refresh)
 	authGroup.POST("/logout", h.Logout, auth, clearCookies)
 	authGroup.POST("/logout/all", h.LogoutAll, auth, clearCookies)
 
 	e.GET("/users", h.GetAll)
+	e.GET("/users/me/export", h.Export, auth)
 	e.GET("/users/:id", h.GetByID)
 	e.PUT("/users/:id", h.Update, auth)
 	e.DELETE("/users/:id", h.Delete, auth)
@@ -58,6 +63,7 @@ func Init(
 // @Produce json
 // @Security ApiKeyAuth
 // @Success 200 {object} models.User
+// @Header 200 {string} ETag "User version"
 // @Failure 400,401,404,500 {object} swagger.Error
 // @Router /auth/me [post]
 func (h *handler) Me(c echo.Context) error {
@@ -69,6 +75,8 @@ func (h *handler) Me(c echo.Context) error {
 		return err
 	}
 
+	c.Response().Header().Set(utils.HeaderETag, utils.ETag(res.UpdatedAt))
+
 	return c.JSON(http.StatusOK, res)
 }
 
@@ -77,7 +85,7 @@ func (h *handler) Me(c echo.Context) error {
 // @Summary Get all users
 // @Accept json
 // @Produce json
-// @Success 200 {object} models.UsersList
+// @Success 200 {object} models.ProfilesList
 // @Failure 500 {object} swagger.Error
 // @Router /users [get]
 func (h *handler) GetAll(c echo.Context) error {
@@ -87,34 +95,47 @@ func (h *handler) GetAll(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, &models.UsersList{
-		TotalCount: len(res),
-		Users:      res,
+	profiles := make([]models.Profile, 0, len(res))
+	for i := range res {
+		profiles = append(profiles, res[i].Profile())
+	}
+
+	return c.JSON(http.StatusOK, &models.ProfilesList{
+		TotalCount: len(profiles),
+		Profiles:   profiles,
 	})
 }
 
 // GetByID godoc
 // @Tags Users
-// @Summary Get user by ID
+// @Summary Get user profile by ID or handle
 // @Accept json
 // @Produce json
-// @Param id path string true "User ID"
-// @Success 200 {object} models.User
+// @Param id path string true "User ID or handle"
+// @Success 200 {object} models.Profile
+// @Header 200 {string} ETag "User version"
 // @Failure 400,404,500 {object} swagger.Error
 // @Router /users/{id} [get]
 func (h *handler) GetByID(c echo.Context) error {
-	id, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		return echo.ErrNotFound
+	var (
+		user models.User
+		err  error
+	)
+
+	if id, parseErr := uuid.Parse(c.Param("id")); parseErr == nil {
+		user, err = h.userUseCase.GetByID(id)
+	} else {
+		user, err = h.userUseCase.GetByHandle(c.Param("id"))
 	}
 
-	user, err := h.userUseCase.GetByID(id)
 	if err != nil {
 		h.log.Errorf("auth.UseCase.GetByID: %v", err)
 		return err
 	}
 
-	return c.JSON(http.StatusOK, user)
+	c.Response().Header().Set(utils.HeaderETag, utils.ETag(user.UpdatedAt))
+
+	return c.JSON(http.StatusOK, user.Profile())
 }
 
 // Login godoc
@@ -139,6 +160,12 @@ func (h *handler) Login(c echo.Context) error {
 		return err
 	}
 
+	if user.Restored {
+		h.audit(c, user.User.ID, models.AuditDeletionCancelled)
+	}
+
+	h.audit(c, user.User.ID, models.AuditLogin)
+
 	h.setCookies(c, user)
 
 	return c.JSON(http.StatusOK, user)
@@ -147,11 +174,13 @@ func (h *handler) Login(c echo.Context) error {
 // Register godoc
 // @Tags Auth
 // @Summary New user
+// @Description The email of a deleted account stays taken until the account is erased at the end
+// @Description of its grace period; registering with it returns 409 and logging in restores the account.
 // @Accept json
 // @Produce json
-// @Param body body swagger.UserRequest true "Body"
+// @Param body body swagger.RegisterUser true "Body"
 // @Success 201 {object} models.AuthUser
-// @Failure 400,500 {object} swagger.Error
+// @Failure 400,409,500 {object} swagger.Error
 // @Router /auth/register [post]
 func (h *handler) Register(c echo.Context) error {
 	u := new(models.User)
@@ -166,11 +195,42 @@ func (h *handler) Register(c echo.Context) error {
 		return err
 	}
 
+	h.audit(c, createdUser.User.ID, models.AuditRegister)
+
 	h.setCookies(c, createdUser)
 
 	return c.JSON(http.StatusCreated, createdUser)
 }
 
+// Restore godoc
+// @Tags Auth
+// @Summary Restore deleted user
+// @Accept json
+// @Produce json
+// @Param body body swagger.UserRequest true "Body"
+// @Success 200 {object} models.AuthUser
+// @Failure 400,401,404,500 {object} swagger.Error
+// @Router /auth/restore [post]
+func (h *handler) Restore(c echo.Context) error {
+	u := new(models.User)
+
+	if err := c.Bind(u); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	user, err := h.userUseCase.Restore(u)
+	if err != nil {
+		h.log.Errorf("auth.UseCase.Restore: %v", err)
+		return err
+	}
+
+	h.audit(c, user.User.ID, models.AuditDeletionCancelled)
+
+	h.setCookies(c, user)
+
+	return c.JSON(http.StatusOK, user)
+}
+
 // Refresh godoc
 // @Tags Auth
 // @Summary Using the refresh token
@@ -225,16 +285,49 @@ func (h *handler) Refresh(c echo.Context) error {
 	return c.JSON(http.StatusOK, user)
 }
 
+// ConfirmEmail godoc
+// @Tags Auth
+// @Summary Confirm a new email address
+// @Description Switches the account to the address the confirmation link was sent to.
+// @Accept json
+// @Produce json
+// @Param token query string true "Confirmation token"
+// @Success 200 {object} models.User
+// @Failure 400,404,409,500 {object} swagger.Error
+// @Router /auth/email/confirm [get]
+func (h *handler) ConfirmEmail(c echo.Context) error {
+	token := c.QueryParam("token")
+	if token == "" {
+		return echo.ErrBadRequest
+	}
+
+	user, err := h.userUseCase.ConfirmEmail(token)
+	if err != nil {
+		h.log.Errorf("auth.UseCase.ConfirmEmail: %v", err)
+		return err
+	}
+
+	h.audit(c, user.ID, models.AuditEmailChange)
+
+	return c.JSON(http.StatusOK, user)
+}
+
 // Update godoc
 // @Summary Update user
+// @Description Changing the email or password requires current_password.
+// @Description A new email is only applied once confirmed through the link sent to it,
+// @Description and a new password logs out every other session.
+// @Description Profile fields left out keep their value, and an empty string clears them.
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID"
+// @Param If-Match header string false "ETag of the user being edited"
 // @Param body body swagger.UpdateUser true "Body"
 // @Security ApiKeyAuth
 // @Success 200 {object} models.User
-// @Failure 400,401,403,404,500 {object} swagger.Error
+// @Header 200 {string} ETag "User version"
+// @Failure 400,401,403,404,409,412,500 {object} swagger.Error
 // @Router /users/{id} [put]
 func (h *handler) Update(c echo.Context) error {
 	id, err := uuid.Parse(c.Param("id"))
@@ -254,12 +347,33 @@ func (h *handler) Update(c echo.Context) error {
 
 	u.ID = id
 
-	updatedUser, err := h.userUseCase.Update(u)
+	u.UpdatedAt, err = utils.GetIfMatch(c)
+	if err != nil {
+		return repositories.ErrUserConflict
+	}
+
+	passwordChange := u.Password != ""
+
+	updatedUser, err := h.userUseCase.Update(u, &utils.TokenDetails{
+		AtID: utils.GetCtxAccessID(c),
+		RtID: utils.GetCtxRefreshID(c),
+	})
 	if err != nil {
 		h.log.Errorf("auth.UseCase.Update: %v", err)
 		return err
 	}
 
+	h.audit(c, id, models.AuditUpdate)
+
+	if passwordChange {
+		h.audit(c, id, models.AuditPasswordChange)
+	}
+
+	c.Response().Header().Set(
+		utils.HeaderETag,
+		utils.ETag(updatedUser.UpdatedAt),
+	)
+
 	return c.JSON(http.StatusOK, updatedUser)
 }
 
@@ -270,7 +384,8 @@ func (h *handler) Update(c echo.Context) error {
 // @Produce json
 // @Param id path string true "User ID"
 // @Security ApiKeyAuth
-// @Success 204
+// @Description The account is erased after a grace period; logging in before then cancels the deletion.
+// @Success 202 {object} models.AccountDeletion
 // @Failure 400,401,403,404,500 {object} swagger.Error
 // @Router /users/{id} [delete]
 func (h *handler) Delete(c echo.Context) error {
@@ -283,12 +398,69 @@ func (h *handler) Delete(c echo.Context) error {
 		return echo.ErrForbidden
 	}
 
-	if err := h.userUseCase.Delete(id); err != nil {
+	res, err := h.userUseCase.Delete(
+		id,
+		auditEntry(c, id, models.AuditDeletionRequest),
+	)
+	if err != nil {
 		h.log.Errorf("auth.UseCase.Delete: %v", err)
 		return err
 	}
 
-	return c.NoContent(http.StatusNoContent)
+	return c.JSON(http.StatusAccepted, res)
+}
+
+// Export godoc
+// @Tags Users
+// @Summary Export user data
+// @Description Returns the caller's profile, articles, sessions and audit log as a JSON or ZIP download.
+// @Accept json
+// @Produce json,application/zip
+// @Param format query string false "Archive format" Enums(json, zip)
+// @Security ApiKeyAuth
+// @Success 200 {object} models.UserExport
+// @Failure 400,401,404,500 {object} swagger.Error
+// @Router /users/me/export [get]
+func (h *handler) Export(c echo.Context) error {
+	format := c.QueryParam("format")
+	if format == "" {
+		format = "json"
+	}
+
+	if format != "json" && format != "zip" {
+		return echo.NewHTTPError(
+			http.StatusBadRequest,
+			"format must be json or zip",
+		)
+	}
+
+	userID := utils.GetCtxID(c)
+
+	res, err := h.userUseCase.Export(userID)
+	if err != nil {
+		h.log.Errorf("auth.UseCase.Export: %v", err)
+		return err
+	}
+
+	h.audit(c, userID, models.AuditExport)
+
+	filename := "export-" + userID.String() + "." + format
+	c.Response().Header().Set(
+		echo.HeaderContentDisposition,
+		mime.FormatMediaType("attachment", map[string]string{"filename": filename}),
+	)
+
+	if format == "json" {
+		return c.JSONPretty(http.StatusOK, res, "  ")
+	}
+
+	archive, err := zipExport(res)
+	if err != nil {
+		h.log.Errorf("zipExport: %v", err)
+		return echo.ErrInternalServerError
+	}
+
+	return c.Blob(http.StatusOK, "application/zip", archive)
 }
 
 // Logout godoc
@@ -313,6 +485,8 @@ func (h *handler) Logout(c echo.Context) error {
 		return err
 	}
 
+	h.audit(c, userID, models.AuditLogout)
+
 	return c.NoContent(http.StatusNoContent)
 }
 
@@ -333,9 +507,32 @@ func (h *handler) LogoutAll(c echo.Context) error {
 		return err
 	}
 
+	h.audit(c, userID, models.AuditLogoutAll)
+
 	return c.NoContent(http.StatusNoContent)
 }
 
+// audit records an account event. A failure is logged but does not fail
+// the request, the event itself has already happened.
+func (h *handler) audit(c echo.Context, userID uuid.UUID, action string) {
+	if err := h.userUseCase.Audit(auditEntry(c, userID, action)); err != nil {
+		h.log.Errorf("auth.UseCase.Audit: %v", err)
+	}
+}
+
+func auditEntry(
+	c echo.Context,
+	userID uuid.UUID,
+	action string,
+) *models.AuditEntry {
+	return &models.AuditEntry{
+		UserID:    userID,
+		Action:    action,
+		IP:        c.RealIP(),
+		UserAgent: utils.Truncate(c.Request().UserAgent(), 500),
+	}
+}
+
 func (h *handler) setCookies(c echo.Context, user *models.AuthUser) {
 	c.SetCookie(&http.Cookie{
 		Name:     "access_token",
@@ -1,16 +1,62 @@
 package repository
 
 var (
-	getUserQuery  = `SELECT * FROM users WHERE id = $1`
-	getUsersQuery = `SELECT id, email, updated_at, created_at 
-								FROM users ORDER BY created_at DESC`
-	createUserQuery = `INSERT INTO users (email, "password") 
-								VALUES ($1, $2) RETURNING *`
+	getUserQuery       = `SELECT * FROM users WHERE id = $1 AND deleted_at IS NULL`
+	getUsersByIDsQuery = `SELECT * FROM users WHERE id = ANY($1) AND deleted_at IS NULL`
+	getUsersQuery      = `SELECT id, email, "role", handle, display_name, bio, avatar_url, 
+									updated_at, created_at 
+								FROM users WHERE deleted_at IS NULL 
+								ORDER BY created_at DESC`
+	createUserQuery = `INSERT INTO users 
+								(email, "password", handle, display_name, bio, avatar_url) 
+								VALUES ($1, $2, $3, COALESCE($4, ''), COALESCE($5, ''), COALESCE($6, '')) 
+								RETURNING *`
 	updateUserQuery = `UPDATE users 
 								SET email = COALESCE(NULLIF($1, ''), email), 
 									"password" = COALESCE(NULLIF($2, ''), "password"), 
+									handle = COALESCE(NULLIF($5, ''), handle), 
+									display_name = COALESCE($6, display_name), 
+									bio = COALESCE($7, bio), 
+									avatar_url = COALESCE($8, avatar_url), 
 									updated_at = now() 
-								WHERE id = $3 RETURNING *`
-	deleteUserQuery      = `DELETE FROM users WHERE id = $1`
-	findUserByEmailQuery = `SELECT * FROM users WHERE email = $1`
+								WHERE id = $3 AND deleted_at IS NULL 
+								AND ($4::timestamptz IS NULL OR updated_at = $4) 
+								RETURNING *`
+	setPasswordQuery = `UPDATE users SET "password" = $2 WHERE id = $1`
+	setRoleQuery     = `UPDATE users SET "role" = $2, updated_at = now() 
+								WHERE id = $1 AND deleted_at IS NULL 
+								RETURNING *`
+	deleteUserQuery = `UPDATE users SET deleted_at = now(), erase_at = $2 
+								WHERE id = $1 AND deleted_at IS NULL 
+								RETURNING deleted_at`
+	deleteUserArticlesQuery = `UPDATE articles SET deleted_at = $2 
+								WHERE author_id = $1 AND deleted_at IS NULL 
+								RETURNING id`
+	getDeletedUserQuery = `SELECT deleted_at FROM users 
+								WHERE id = $1 AND deleted_at IS NOT NULL 
+								FOR UPDATE`
+	restoreUserQuery = `UPDATE users SET deleted_at = NULL, erase_at = NULL 
+								WHERE id = $1 RETURNING *`
+	restoreUserArticlesQuery = `UPDATE articles SET deleted_at = NULL 
+								WHERE author_id = $1 AND deleted_at = $2`
+	// setEraseAtQuery schedules the erasure of accounts deleted before
+	// erase_at existed, using the current grace period in seconds.
+	setEraseAtQuery = `UPDATE users SET erase_at = deleted_at + make_interval(secs => $1) 
+								WHERE deleted_at IS NOT NULL AND erase_at IS NULL`
+	// purgeUserAttachmentsQuery removes what the cascade from users would,
+	// returning the keys so that the blobs can be deleted too.
+	purgeUserAttachmentsQuery = `DELETE FROM attachments 
+								WHERE uploader_id IN (SELECT id FROM users WHERE erase_at < $1) 
+								OR article_id IN (SELECT a.id FROM articles a 
+									JOIN users u ON u.id = a.author_id WHERE u.erase_at < $1) 
+								RETURNING key`
+	purgeUsersQuery             = `DELETE FROM users WHERE erase_at < $1`
+	findUserByEmailQuery        = `SELECT * FROM users WHERE email = $1 AND deleted_at IS NULL`
+	findUserByHandleQuery       = `SELECT * FROM users WHERE handle = $1 AND deleted_at IS NULL`
+	findDeletedUserByEmailQuery = `SELECT * FROM users WHERE email = $1 AND deleted_at IS NOT NULL`
+
+	getAuditQuery = `SELECT * FROM audit_log WHERE user_id = $1 
+								ORDER BY created_at DESC, id DESC`
+	createAuditQuery = `INSERT INTO audit_log (user_id, action, ip, user_agent) 
+								VALUES ($1, $2, $3, $4)`
 )
@@ -3,6 +3,7 @@ package repository
 import (
 	"database/sql"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -10,13 +11,14 @@ import (
 	"github.com/lib/pq"
 	"github.com/slavtov/clean-architecture/internal/domain/models"
 	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/pkg/store/postgres"
 )
 
 type pgRepository struct {
-	db *sqlx.DB
+	db postgres.DB
 }
 
-func NewPGRepository(db *sqlx.DB) repositories.PGUserRepository {
+func NewPGRepository(db postgres.DB) repositories.PGUserRepository {
 	return &pgRepository{db}
 }
 
@@ -51,6 +53,20 @@ func (r *pgRepository) GetByID(id uuid.UUID) (models.User, error) {
 	return user, nil
 }
 
+func (r *pgRepository) GetByIDs(ids []uuid.UUID) ([]models.User, error) {
+	var users []models.User
+
+	if err := r.db.Select(
+		&users,
+		getUsersByIDsQuery,
+		pq.Array(ids),
+	); err != nil {
+		return users, echo.ErrInternalServerError
+	}
+
+	return users, nil
+}
+
 func (r *pgRepository) FindByEmail(email string) (models.User, error) {
 	var user models.User
 
@@ -71,6 +87,24 @@ func (r *pgRepository) FindByEmail(email string) (models.User, error) {
 	return user, nil
 }
 
+func (r *pgRepository) GetByHandle(handle string) (models.User, error) {
+	var user models.User
+
+	if err := r.db.Get(
+		&user,
+		findUserByHandleQuery,
+		handle,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return user, echo.ErrNotFound
+		}
+
+		return user, echo.ErrBadRequest
+	}
+
+	return user, nil
+}
+
 func (r *pgRepository) Store(u *models.User) (*models.User, error) {
 	var user models.User
 
@@ -78,12 +112,13 @@ func (r *pgRepository) Store(u *models.User) (*models.User, error) {
 		createUserQuery,
 		u.Email,
 		u.Password,
+		u.Handle,
+		u.DisplayName,
+		u.Bio,
+		u.AvatarURL,
 	).StructScan(&user); err != nil {
-		if err.(*pq.Error).Code == "23505" {
-			return nil, echo.NewHTTPError(
-				http.StatusBadRequest,
-				"email already exists",
-			)
+		if err := uniqueViolation(err); err != nil {
+			return nil, err
 		}
 
 		return nil, echo.ErrBadRequest
@@ -100,6 +135,51 @@ func (r *pgRepository) Update(a *models.User) (*models.User, error) {
 		a.Email,
 		a.Password,
 		a.ID,
+		postgres.NullTime(a.UpdatedAt),
+		a.Handle,
+		a.DisplayName,
+		a.Bio,
+		a.AvatarURL,
+	).StructScan(&user); err != nil {
+		if err := uniqueViolation(err); err != nil {
+			return nil, err
+		}
+
+		if err != sql.ErrNoRows {
+			return nil, echo.ErrBadRequest
+		}
+
+		if a.UpdatedAt.IsZero() {
+			return nil, echo.ErrNotFound
+		}
+
+		if _, err := r.GetByID(a.ID); err != nil {
+			return nil, err
+		}
+
+		return nil, repositories.ErrUserConflict
+	}
+
+	return &user, nil
+}
+
+// SetPassword replaces the password hash without touching updated_at, so
+// upgrading a hash does not change the version of the user.
+func (r *pgRepository) SetPassword(id uuid.UUID, hash string) error {
+	if _, err := r.db.Exec(setPasswordQuery, id, hash); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (r *pgRepository) SetRole(id uuid.UUID, role string) (*models.User, error) {
+	var user models.User
+
+	if err := r.db.QueryRowx(
+		setRoleQuery,
+		id,
+		role,
 	).StructScan(&user); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, echo.ErrNotFound
@@ -111,19 +191,189 @@ func (r *pgRepository) Update(a *models.User) (*models.User, error) {
 	return &user, nil
 }
 
-func (r *pgRepository) Delete(id uuid.UUID) error {
-	res, err := r.db.Exec(deleteUserQuery, id)
+func (r *pgRepository) FindDeletedByEmail(email string) (models.User, error) {
+	var user models.User
+
+	if err := r.db.QueryRowx(
+		findDeletedUserByEmailQuery,
+		email,
+	).StructScan(&user); err != nil {
+		if err == sql.ErrNoRows {
+			return user, echo.NewHTTPError(
+				http.StatusBadRequest,
+				"deleted user is not found",
+			)
+		}
+
+		return user, echo.ErrBadRequest
+	}
+
+	return user, nil
+}
+
+// Delete soft-deletes the user together with their articles, schedules
+// the account to be erased at eraseAt and returns the IDs of the articles
+// that were deleted along with it.
+func (r *pgRepository) Delete(
+	id uuid.UUID,
+	eraseAt time.Time,
+) ([]uuid.UUID, error) {
+	var articleIDs []uuid.UUID
+
+	err := postgres.WithTx(r.db, func(tx *sqlx.Tx) error {
+		var deletedAt time.Time
+
+		if err := tx.Get(
+			&deletedAt,
+			deleteUserQuery,
+			id,
+			eraseAt,
+		); err != nil {
+			if err == sql.ErrNoRows {
+				return echo.ErrNotFound
+			}
+
+			return echo.ErrBadRequest
+		}
+
+		if err := tx.Select(
+			&articleIDs,
+			deleteUserArticlesQuery,
+			id,
+			deletedAt,
+		); err != nil {
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
+
+	return articleIDs, err
+}
+
+func (r *pgRepository) Restore(id uuid.UUID) (*models.User, error) {
+	var user models.User
+
+	err := postgres.WithTx(r.db, func(tx *sqlx.Tx) error {
+		var deletedAt time.Time
+
+		if err := tx.Get(
+			&deletedAt,
+			getDeletedUserQuery,
+			id,
+		); err != nil {
+			if err == sql.ErrNoRows {
+				return echo.ErrNotFound
+			}
+
+			return echo.ErrBadRequest
+		}
+
+		if err := tx.QueryRowx(
+			restoreUserQuery,
+			id,
+		).StructScan(&user); err != nil {
+			return echo.ErrBadRequest
+		}
+
+		if _, err := tx.Exec(
+			restoreUserArticlesQuery,
+			id,
+			deletedAt,
+		); err != nil {
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
 	if err != nil {
-		return echo.ErrBadRequest
+		return nil, err
 	}
 
-	rowsAffected, err := res.RowsAffected()
+	return &user, nil
+}
+
+// Purge erases the accounts whose grace period ended before the given
+// time, along with everything that cascades from them, and returns the
+// keys of the attachment blobs that went with them.
+func (r *pgRepository) Purge(
+	before time.Time,
+	grace time.Duration,
+) (int64, []string, error) {
+	var (
+		n    int64
+		keys []string
+	)
+
+	err := postgres.WithTx(r.db, func(tx *sqlx.Tx) error {
+		if _, err := tx.Exec(setEraseAtQuery, grace.Seconds()); err != nil {
+			return echo.ErrInternalServerError
+		}
+
+		if err := tx.Select(&keys, purgeUserAttachmentsQuery, before); err != nil {
+			return echo.ErrInternalServerError
+		}
+
+		res, err := tx.Exec(purgeUsersQuery, before)
+		if err != nil {
+			return echo.ErrInternalServerError
+		}
+
+		if n, err = res.RowsAffected(); err != nil {
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
 	if err != nil {
-		return echo.ErrInternalServerError
+		return 0, nil, err
+	}
+
+	return n, keys, nil
+}
+
+func uniqueViolation(err error) error {
+	pqErr, ok := err.(*pq.Error)
+	if !ok || pqErr.Code != "23505" {
+		return nil
+	}
+
+	if pqErr.Constraint == "users_handle_key" {
+		return echo.NewHTTPError(
+			http.StatusConflict,
+			"handle is already taken",
+		)
 	}
 
-	if rowsAffected == 0 {
-		return echo.ErrNotFound
+	return echo.NewHTTPError(
+		http.StatusConflict,
+		"email already exists",
+	)
+}
+
+func (r *pgRepository) GetAudit(userID uuid.UUID) ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+
+	if err := r.db.Select(
+		&entries,
+		getAuditQuery,
+		userID,
+	); err != nil {
+		return entries, echo.ErrInternalServerError
+	}
+
+	return entries, nil
+}
+
+func (r *pgRepository) StoreAudit(entry *models.AuditEntry) error {
+	if _, err := r.db.Exec(
+		createAuditQuery,
+		entry.UserID,
+		entry.Action,
+		entry.IP,
+		entry.UserAgent,
+	); err != nil {
+		return echo.ErrInternalServerError
 	}
 
 	return nil
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,8 +18,9 @@ type redisRepository struct {
 }
 
 const (
-	authPrefix = "auth"
-	userPrefix = "users"
+	authPrefix        = "auth"
+	userPrefix        = "users"
+	emailChangePrefix = "email_change"
 )
 
 func NewRedisRepository(rdb redis.Store) repositories.RedisUserRepository {
@@ -40,6 +42,39 @@ func (r *redisRepository) GetByID(id uuid.UUID) (models.User, error) {
 	return user, nil
 }
 
+func (r *redisRepository) GetByIDs(ids []uuid.UUID) ([]models.User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, utils.GetRedisKey(userPrefix, id.String()))
+	}
+
+	values, err := r.redis.MGet(keys...)
+	if err != nil {
+		return nil, echo.ErrInternalServerError
+	}
+
+	users := make([]models.User, 0, len(values))
+	for _, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		var user models.User
+		if err := json.Unmarshal([]byte(s), &user); err != nil {
+			continue
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
 func (r *redisRepository) GetTokenInfo(
 	id uuid.UUID,
 	tokenID uuid.UUID,
@@ -56,6 +91,38 @@ func (r *redisRepository) GetTokenInfo(
 	return uuid.Parse(res)
 }
 
+// GetSessions lists the live tokens of the user with their expiry.
+func (r *redisRepository) GetSessions(id uuid.UUID) ([]models.Session, error) {
+	prefix := utils.GetRedisKey(authPrefix, id.String(), "")
+
+	keys, err := r.redis.Keys(prefix + "*")
+	if err != nil {
+		return nil, echo.ErrInternalServerError
+	}
+
+	now := time.Now()
+	sessions := make([]models.Session, 0, len(keys))
+
+	for _, key := range keys {
+		tokenID, err := uuid.Parse(strings.TrimPrefix(key, prefix))
+		if err != nil {
+			continue
+		}
+
+		ttl, err := r.redis.TTL(key)
+		if err != nil || ttl < 0 {
+			continue
+		}
+
+		sessions = append(sessions, models.Session{
+			ID:        tokenID,
+			ExpiresAt: now.Add(ttl),
+		})
+	}
+
+	return sessions, nil
+}
+
 func (r *redisRepository) SetToken(
 	id uuid.UUID,
 	tokenID uuid.UUID,
@@ -94,6 +161,58 @@ func (r *redisRepository) SetUser(
 	return nil
 }
 
+func (r *redisRepository) SetEmailChange(
+	token string,
+	change *models.EmailChange,
+	exp time.Duration,
+) error {
+	res, err := json.Marshal(change)
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	if err = r.redis.Set(utils.GetRedisKey(
+		emailChangePrefix,
+		token,
+	), res, exp); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// TakeEmailChange returns the pending change for the token and removes
+// it, so that every confirmation link works only once.
+func (r *redisRepository) TakeEmailChange(
+	token string,
+) (models.EmailChange, error) {
+	var change models.EmailChange
+
+	res, err := r.redis.GetDel(utils.GetRedisKey(emailChangePrefix, token))
+	if err != nil {
+		return change, echo.ErrNotFound
+	}
+
+	if err = json.Unmarshal([]byte(res), &change); err != nil {
+		return change, echo.ErrInternalServerError
+	}
+
+	return change, nil
+}
+
+// DeleteEmailChange drops the pending change for the token, e.g. when it
+// could not be sent.
+func (r *redisRepository) DeleteEmailChange(token string) error {
+	if err := r.redis.Del(utils.GetRedisKey(
+		emailChangePrefix,
+		token,
+	)); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
 func (r *redisRepository) Delete(keys ...string) error {
 	if err := r.redis.Del(keys...); err != nil {
 		return echo.ErrNotFound
@@ -109,3 +228,31 @@ func (r *redisRepository) DeleteAll(pattern string) error {
 
 	return nil
 }
+
+func (r *redisRepository) DeleteAllExcept(
+	pattern string,
+	keep ...string,
+) error {
+	keys, err := r.redis.Keys(pattern)
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	kept := make(map[string]bool, len(keep))
+	for _, key := range keep {
+		kept[key] = true
+	}
+
+	var stale []string
+	for _, key := range keys {
+		if !kept[key] {
+			stale = append(stale, key)
+		}
+	}
+
+	if len(stale) == 0 {
+		return nil
+	}
+
+	return r.Delete(stale...)
+}
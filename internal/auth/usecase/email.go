@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+)
+
+const (
+	confirmEmailSubject = "Confirm your new email address"
+	confirmEmailBody    = `Someone asked to change the email address of your account to this one.
+
+To confirm the change, open the link below:
+
+%s
+
+If it wasn't you, ignore this message and the address will stay the same.
+`
+	emailChangeSubject = "Your email address is being changed"
+	emailChangeBody    = `Someone asked to change the email address of your account to %s.
+
+The change only takes effect once the new address is confirmed. If it
+wasn't you, change your password right away.
+`
+)
+
+// ConfirmEmail switches the user to the address from the pending change.
+func (u *usecase) ConfirmEmail(token string) (*models.User, error) {
+	change, err := u.redisRepository.TakeEmailChange(token)
+	if err != nil {
+		u.log.Errorf("auth.redisRepository.TakeEmailChange: %v", err)
+		return nil, echo.NewHTTPError(
+			http.StatusBadRequest,
+			"invalid or expired token",
+		)
+	}
+
+	res, err := u.pgRepository.Update(&models.User{
+		ID:    change.UserID,
+		Email: change.Email,
+	})
+	if err != nil {
+		u.log.Errorf("auth.pgRepository.Update: %v", err)
+		return nil, err
+	}
+
+	res.SanitizePassword()
+
+	if err := u.redisRepository.SetUser(
+		res,
+		time.Second*cacheDuration,
+	); err != nil {
+		u.log.Errorf("auth.redisRepository.SetUser: %v", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// requestEmailChange stores a pending change and returns its token. The
+// emails are only sent by sendEmailChange once the rest of the update has
+// gone through.
+func (u *usecase) requestEmailChange(
+	user *models.User,
+	email string,
+) (string, error) {
+	if _, err := u.pgRepository.FindByEmail(email); err == nil {
+		return "", echo.NewHTTPError(
+			http.StatusConflict,
+			"email already exists",
+		)
+	}
+
+	token, err := newToken()
+	if err != nil {
+		u.log.Errorf("auth.newToken: %v", err)
+		return "", echo.ErrInternalServerError
+	}
+
+	if err := u.redisRepository.SetEmailChange(
+		token,
+		&models.EmailChange{UserID: user.ID, Email: email},
+		time.Second*time.Duration(u.cfg.Account.EmailChangeTTL),
+	); err != nil {
+		u.log.Errorf("auth.redisRepository.SetEmailChange: %v", err)
+		return "", err
+	}
+
+	return token, nil
+}
+
+// sendEmailChange sends the confirmation link of a pending change to the
+// new address and a notice to the current one. Without the confirmation
+// the change cannot be completed, so it is dropped.
+func (u *usecase) sendEmailChange(
+	user *models.User,
+	email string,
+	token string,
+) error {
+	link := u.cfg.Server.BaseURL + "/api/auth/email/confirm?token=" +
+		url.QueryEscape(token)
+
+	if err := u.mailer.Send(
+		email,
+		confirmEmailSubject,
+		fmt.Sprintf(confirmEmailBody, link),
+	); err != nil {
+		u.log.Errorf("auth.mailer.Send: %v", err)
+
+		if err := u.redisRepository.DeleteEmailChange(token); err != nil {
+			u.log.Errorf("auth.redisRepository.DeleteEmailChange: %v", err)
+		}
+
+		return echo.NewHTTPError(
+			http.StatusInternalServerError,
+			"the confirmation email could not be sent, request the change again",
+		)
+	}
+
+	if err := u.mailer.Send(
+		user.Email,
+		emailChangeSubject,
+		fmt.Sprintf(emailChangeBody, email),
+	); err != nil {
+		u.log.Errorf("auth.mailer.Send: %v", err)
+	}
+
+	return nil
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -84,3 +84,13 @@ func (u *usecase) LogoutAll(id uuid.UUID) error {
 		"*",
 	))
 }
+
+// LogoutOthers ends every session of the user except the one the tokens
+// belong to.
+func (u *usecase) LogoutOthers(id uuid.UUID, td *utils.TokenDetails) error {
+	return u.redisRepository.DeleteAllExcept(
+		utils.GetRedisKey(authPrefix, id.String(), "*"),
+		utils.GetRedisKey(authPrefix, id.String(), td.AtID.String()),
+		utils.GetRedisKey(authPrefix, id.String(), td.RtID.String()),
+	)
+}
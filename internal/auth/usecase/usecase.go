@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,6 +12,8 @@ import (
 	"github.com/slavtov/clean-architecture/internal/domain/repositories"
 	"github.com/slavtov/clean-architecture/internal/domain/usecases"
 	"github.com/slavtov/clean-architecture/pkg/logger"
+	"github.com/slavtov/clean-architecture/pkg/mailer"
+	"github.com/slavtov/clean-architecture/pkg/password"
 	"github.com/slavtov/clean-architecture/pkg/utils"
 )
 
@@ -18,22 +21,52 @@ type usecase struct {
 	cfg             *config.Config
 	pgRepository    repositories.PGUserRepository
 	redisRepository repositories.RedisUserRepository
+	mailer          mailer.Mailer
+	blobStore       repositories.BlobStore
+	uow             repositories.UnitOfWork
+	articleUseCase  usecases.ArticleUseCase
+	hasher          *password.Hasher
+	policy          *password.Policy
+	breachChecker   password.BreachChecker
 	log             logger.Logger
 }
 
-const cacheDuration = 3600
+const (
+	cacheDuration = 3600
+	userPrefix    = "users"
+	articlePrefix = "articles"
+)
 
 func New(
 	cfg *config.Config,
 	pg repositories.PGUserRepository,
 	redis repositories.RedisUserRepository,
+	mailer mailer.Mailer,
+	blobStore repositories.BlobStore,
+	uow repositories.UnitOfWork,
+	au usecases.ArticleUseCase,
+	hasher *password.Hasher,
 	log logger.Logger,
 ) usecases.UserUseCase {
 	return &usecase{
 		cfg:             cfg,
 		pgRepository:    pg,
 		redisRepository: redis,
-		log:             log,
+		mailer:          mailer,
+		blobStore:       blobStore,
+		uow:             uow,
+		articleUseCase:  au,
+		hasher:          hasher,
+		policy: &password.Policy{
+			MinLength:     cfg.Password.MinLength,
+			MaxLength:     cfg.Password.MaxLength,
+			RequireUpper:  cfg.Password.RequireUpper,
+			RequireLower:  cfg.Password.RequireLower,
+			RequireDigit:  cfg.Password.RequireDigit,
+			RequireSymbol: cfg.Password.RequireSymbol,
+		},
+		breachChecker: password.NewBreachChecker(cfg.Password.BreachedPath),
+		log:           log,
 	}
 }
 
@@ -76,6 +109,68 @@ func (u *usecase) GetByID(id uuid.UUID) (models.User, error) {
 	return res, nil
 }
 
+// GetByIDs returns the existing users among ids, in no particular order.
+// Cached users are taken from Redis and the rest are fetched with a single
+// query and cached.
+func (u *usecase) GetByIDs(ids []uuid.UUID) ([]models.User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	res, err := u.redisRepository.GetByIDs(ids)
+	if err != nil {
+		u.log.Errorf("auth.redisRepository.GetByIDs: %v", err)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, user := range res {
+		seen[user.ID] = true
+	}
+
+	var misses []uuid.UUID
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			misses = append(misses, id)
+		}
+	}
+
+	if len(misses) == 0 {
+		return res, nil
+	}
+
+	users, err := u.pgRepository.GetByIDs(misses)
+	if err != nil {
+		u.log.Errorf("auth.pgRepository.GetByIDs: %v", err)
+		return nil, err
+	}
+
+	for i := range users {
+		users[i].SanitizePassword()
+
+		if err := u.redisRepository.SetUser(
+			&users[i],
+			time.Second*cacheDuration,
+		); err != nil {
+			u.log.Errorf("auth.redisRepository.SetUser: %v", err)
+		}
+	}
+
+	return append(res, users...), nil
+}
+
+func (u *usecase) GetByHandle(handle string) (models.User, error) {
+	res, err := u.pgRepository.GetByHandle(strings.ToLower(handle))
+	if err != nil {
+		u.log.Errorf("auth.pgRepository.GetByHandle: %v", err)
+		return res, err
+	}
+
+	res.SanitizePassword()
+
+	return res, nil
+}
+
 func (u *usecase) Login(user *models.User) (*models.AuthUser, error) {
 	if err := user.Validate(); err != nil {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -88,19 +183,39 @@ func (u *usecase) Login(user *models.User) (*models.AuthUser, error) {
 	res, err := u.pgRepository.FindByEmail(user.Email)
 	if err != nil {
 		u.log.Errorf("auth.pgRepository.FindByEmail: %v", err)
+
+		// Logging in during the grace period cancels the deletion.
+		if _, deletedErr := u.pgRepository.FindDeletedByEmail(
+			user.Email,
+		); deletedErr == nil {
+			return u.Restore(user)
+		}
+
 		return nil, err
 	}
 
-	if err = res.ComparePassword(user.Password); err != nil {
+	if err = res.ComparePassword(u.hasher, user.Password); err != nil {
 		return nil, echo.ErrUnauthorized
 	}
 
+	u.rehash(&res, user.Password)
+
 	res.SanitizePassword()
 
 	return u.Auth(&res)
 }
 
 func (u *usecase) Store(user *models.User) (*models.AuthUser, error) {
+	res, err := u.Create(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.Auth(res)
+}
+
+// Create registers the user without logging them in.
+func (u *usecase) Create(user *models.User) (*models.User, error) {
 	if err := user.Validate(); err != nil {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
@@ -109,17 +224,43 @@ func (u *usecase) Store(user *models.User) (*models.AuthUser, error) {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	if err := user.HashPassword(); err != nil {
+	if err := u.checkPassword(user.Password, user.Email); err != nil {
+		return nil, err
+	}
+
+	// A deleted account keeps its email until it is erased, so that
+	// logging in can still restore it.
+	if _, err := u.pgRepository.FindDeletedByEmail(user.Email); err == nil {
+		return nil, echo.NewHTTPError(
+			http.StatusConflict,
+			"email belongs to an account pending deletion, log in to restore it",
+		)
+	}
+
+	if err := user.HashPassword(u.hasher); err != nil {
+		u.log.Errorf("auth.HashPassword: %v", err)
 		return nil, echo.ErrInternalServerError
 	}
 
-	res, err := u.pgRepository.Store(user)
-	if err != nil {
-		u.log.Errorf("auth.pgRepository.Store: %v", err)
-		return nil, err
+	if user.Handle == "" {
+		user.Handle = models.GenerateHandle()
 	}
 
-	res.SanitizePassword()
+	var res *models.User
+
+	if err := u.uow.Do(nil, func(r *repositories.TxRepositories) error {
+		var err error
+		if res, err = r.Users.Store(user); err != nil {
+			u.log.Errorf("auth.pgRepository.Store: %v", err)
+			return err
+		}
+
+		res.SanitizePassword()
+
+		return u.emit(r, models.EventUserRegistered, res.ID, res.Profile())
+	}); err != nil {
+		return nil, err
+	}
 
 	if err := u.redisRepository.SetUser(
 		res,
@@ -129,28 +270,85 @@ func (u *usecase) Store(user *models.User) (*models.AuthUser, error) {
 		return nil, err
 	}
 
-	return u.Auth(res)
+	return res, nil
 }
 
-func (u *usecase) Update(user *models.User) (*models.User, error) {
+// Update changes the profile of the user. Changing the email or password
+// requires the current password; a new email only takes effect once it
+// is confirmed, and a new password ends every session but the given one.
+func (u *usecase) Update(
+	user *models.User,
+	session *utils.TokenDetails,
+) (*models.User, error) {
 	if err := user.Validate(); err != nil {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	current, err := u.pgRepository.GetByID(user.ID)
+	if err != nil {
+		u.log.Errorf("auth.pgRepository.GetByID: %v", err)
+		return nil, err
+	}
+
+	newEmail := ""
+	if user.Email != current.Email {
+		newEmail = user.Email
+	}
+
+	if newEmail != "" || user.Password != "" {
+		if user.CurrentPassword == "" ||
+			current.ComparePassword(u.hasher, user.CurrentPassword) != nil {
+			return nil, echo.NewHTTPError(
+				http.StatusForbidden,
+				"current password is incorrect",
+			)
+		}
+	}
+
 	if user.Password != "" {
-		if err := user.HashPassword(); err != nil {
-			return nil, echo.ErrBadRequest
+		for _, email := range []string{current.Email, newEmail} {
+			if err := u.checkPassword(user.Password, email); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := user.HashPassword(u.hasher); err != nil {
+			u.log.Errorf("auth.HashPassword: %v", err)
+			return nil, echo.ErrInternalServerError
 		}
 	}
 
+	var token string
+	if newEmail != "" {
+		if token, err = u.requestEmailChange(&current, newEmail); err != nil {
+			return nil, err
+		}
+	}
+
+	user.Email = ""
+
 	res, err := u.pgRepository.Update(user)
 	if err != nil {
 		u.log.Errorf("auth.pgRepository.Update: %v", err)
+
+		if token != "" {
+			if err := u.redisRepository.DeleteEmailChange(token); err != nil {
+				u.log.Errorf("auth.redisRepository.DeleteEmailChange: %v", err)
+			}
+		}
+
 		return nil, err
 	}
 
 	res.SanitizePassword()
 
+	if user.Password != "" {
+		if err := u.LogoutOthers(user.ID, session); err != nil {
+			u.log.Errorf("auth.LogoutOthers: %v", err)
+			return nil, err
+		}
+	}
+
 	if err := u.redisRepository.SetUser(
 		res,
 		time.Second*cacheDuration,
@@ -159,24 +357,283 @@ func (u *usecase) Update(user *models.User) (*models.User, error) {
 		return nil, err
 	}
 
+	// Nobody is mailed about a change that did not go through.
+	if token != "" {
+		if err := u.sendEmailChange(&current, newEmail, token); err != nil {
+			return nil, err
+		}
+	}
+
 	return res, nil
 }
 
-func (u *usecase) Delete(id uuid.UUID) error {
-	if err := u.pgRepository.Delete(id); err != nil {
-		u.log.Errorf("auth.pgRepository.Delete: %v", err)
-		return err
+// Delete schedules the account to be erased after the grace period. Until
+// then the user can cancel the deletion by logging in. The audit entry is
+// written in the same transaction, so no deletion goes unrecorded.
+func (u *usecase) Delete(
+	id uuid.UUID,
+	audit *models.AuditEntry,
+) (*models.AccountDeletion, error) {
+	eraseAt := time.Now().Add(
+		time.Second * time.Duration(u.cfg.Account.DeletionGracePeriod),
+	)
+
+	var articleIDs []uuid.UUID
+
+	if err := u.uow.Do(nil, func(r *repositories.TxRepositories) error {
+		var err error
+		if articleIDs, err = r.Users.Delete(id, eraseAt); err != nil {
+			u.log.Errorf("auth.pgRepository.Delete: %v", err)
+			return err
+		}
+
+		if err := r.Users.StoreAudit(audit); err != nil {
+			u.log.Errorf("auth.pgRepository.StoreAudit: %v", err)
+			return err
+		}
+
+		return u.emit(r, models.EventUserDeleted, id, &models.UserDeletedPayload{
+			ID:      id,
+			EraseAt: eraseAt,
+		})
+	}); err != nil {
+		return nil, err
 	}
 
-	if err := u.redisRepository.Delete(utils.GetRedisKey(
-		"users",
-		id.String(),
-	)); err != nil {
+	keys := []string{utils.GetRedisKey(userPrefix, id.String())}
+	for _, articleID := range articleIDs {
+		keys = append(keys, utils.GetRedisKey(
+			articlePrefix,
+			articleID.String(),
+		))
+	}
+
+	if err := u.redisRepository.Delete(keys...); err != nil {
 		u.log.Errorf("auth.redisRepository.Delete: %v", err)
-		return err
+		return nil, err
 	}
 
 	if err := u.LogoutAll(id); err != nil {
+		return nil, err
+	}
+
+	return &models.AccountDeletion{EraseAt: eraseAt}, nil
+}
+
+func (u *usecase) Restore(user *models.User) (*models.AuthUser, error) {
+	if err := user.Validate(); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := user.ValidatePassword(); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	deletedUser, err := u.pgRepository.FindDeletedByEmail(user.Email)
+	if err != nil {
+		u.log.Errorf("auth.pgRepository.FindDeletedByEmail: %v", err)
+		return nil, err
+	}
+
+	if err = deletedUser.ComparePassword(u.hasher, user.Password); err != nil {
+		return nil, echo.ErrUnauthorized
+	}
+
+	u.rehash(&deletedUser, user.Password)
+
+	res, err := u.pgRepository.Restore(deletedUser.ID)
+	if err != nil {
+		u.log.Errorf("auth.pgRepository.Restore: %v", err)
+		return nil, err
+	}
+
+	res.SanitizePassword()
+
+	if err := u.redisRepository.SetUser(
+		res,
+		time.Second*cacheDuration,
+	); err != nil {
+		u.log.Errorf("auth.redisRepository.SetUser: %v", err)
+		return nil, err
+	}
+
+	authUser, err := u.Auth(res)
+	if err != nil {
+		return nil, err
+	}
+
+	authUser.Restored = true
+
+	return authUser, nil
+}
+
+func (u *usecase) Purge(before time.Time) (int64, error) {
+	n, keys, err := u.pgRepository.Purge(
+		before,
+		time.Second*time.Duration(u.cfg.Account.DeletionGracePeriod),
+	)
+	if err != nil {
+		u.log.Errorf("auth.pgRepository.Purge: %v", err)
+		return 0, err
+	}
+
+	for _, key := range keys {
+		if err := u.blobStore.Delete(key); err != nil {
+			u.log.Errorf("auth.blobStore.Delete: %v", err)
+		}
+	}
+
+	if n > 0 {
+		u.log.Infof("auth.Purge: %d users purged", n)
+	}
+
+	return n, nil
+}
+
+// Export collects the data kept about the user for a GDPR data request.
+func (u *usecase) Export(id uuid.UUID) (*models.UserExport, error) {
+	user, err := u.pgRepository.GetByID(id)
+	if err != nil {
+		u.log.Errorf("auth.pgRepository.GetByID: %v", err)
+		return nil, err
+	}
+
+	user.SanitizePassword()
+
+	articles, err := u.articleUseCase.GetAll(&models.ArticleFilter{
+		ViewerID: id,
+		AuthorID: &id,
+	})
+	if err != nil {
+		u.log.Errorf("article.UseCase.GetAll: %v", err)
+		return nil, err
+	}
+
+	sessions, err := u.redisRepository.GetSessions(id)
+	if err != nil {
+		u.log.Errorf("auth.redisRepository.GetSessions: %v", err)
+		return nil, err
+	}
+
+	audit, err := u.pgRepository.GetAudit(id)
+	if err != nil {
+		u.log.Errorf("auth.pgRepository.GetAudit: %v", err)
+		return nil, err
+	}
+
+	return &models.UserExport{
+		ExportedAt: time.Now(),
+		Profile:    &user,
+		Articles:   articles,
+		Sessions:   sessions,
+		Audit:      audit,
+	}, nil
+}
+
+func (u *usecase) SetRole(id uuid.UUID, role string) (*models.User, error) {
+	if role != models.RoleUser &&
+		role != models.RoleModerator &&
+		role != models.RoleAdmin {
+		return nil, echo.NewHTTPError(
+			http.StatusBadRequest,
+			"role must be user, moderator or admin",
+		)
+	}
+
+	res, err := u.pgRepository.SetRole(id, role)
+	if err != nil {
+		u.log.Errorf("auth.pgRepository.SetRole: %v", err)
+		return nil, err
+	}
+
+	res.SanitizePassword()
+
+	if err := u.redisRepository.SetUser(
+		res,
+		time.Second*cacheDuration,
+	); err != nil {
+		u.log.Errorf("auth.redisRepository.SetUser: %v", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// checkPassword applies the password policy and the breached password
+// check to a new password of the account with the given email.
+func (u *usecase) checkPassword(plain string, email string) error {
+	if err := u.policy.Check(plain, email); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	breached, err := u.breachChecker.IsBreached(plain)
+	if err != nil {
+		u.log.Errorf("auth.breachChecker.IsBreached: %v", err)
+		return echo.ErrInternalServerError
+	}
+
+	if breached {
+		return echo.NewHTTPError(
+			http.StatusBadRequest,
+			password.ErrBreached.Error(),
+		)
+	}
+
+	if u.cfg.Password.Algorithm == password.Bcrypt &&
+		len(plain) > password.BcryptMaxLen {
+		return echo.NewHTTPError(
+			http.StatusBadRequest,
+			password.ErrTooLong.Error(),
+		)
+	}
+
+	return nil
+}
+
+// rehash upgrades the stored hash of a user who just proved the password,
+// when it was made with another algorithm or weaker parameters. Failing
+// to do so is not fatal, the old hash keeps working.
+func (u *usecase) rehash(user *models.User, plain string) {
+	if !u.hasher.NeedsRehash(user.Password) {
+		return
+	}
+
+	hash, err := u.hasher.Hash(plain)
+	if err != nil {
+		u.log.Errorf("auth.hasher.Hash: %v", err)
+		return
+	}
+
+	if err := u.pgRepository.SetPassword(user.ID, hash); err != nil {
+		u.log.Errorf("auth.pgRepository.SetPassword: %v", err)
+	}
+}
+
+func (u *usecase) Audit(entry *models.AuditEntry) error {
+	if err := u.pgRepository.StoreAudit(entry); err != nil {
+		u.log.Errorf("auth.pgRepository.StoreAudit: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// emit writes an event about the user to the outbox, so it is only
+// published if the transaction commits.
+func (u *usecase) emit(
+	r *repositories.TxRepositories,
+	eventType string,
+	id uuid.UUID,
+	payload interface{},
+) error {
+	event, err := models.NewEvent(eventType, id, payload)
+	if err != nil {
+		u.log.Errorf("auth.NewEvent: %v", err)
+		return echo.ErrInternalServerError
+	}
+
+	if _, err := r.Outbox.Store(event); err != nil {
+		u.log.Errorf("outbox.pgRepository.Store: %v", err)
 		return err
 	}
 
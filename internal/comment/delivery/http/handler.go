@@ -0,0 +1,240 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/config"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/usecases"
+	"github.com/slavtov/clean-architecture/internal/middleware"
+	"github.com/slavtov/clean-architecture/pkg/logger"
+	"github.com/slavtov/clean-architecture/pkg/utils"
+)
+
+type handler struct {
+	commentUseCase usecases.CommentUseCase
+	userUseCase    usecases.UserUseCase
+	log            logger.Logger
+}
+
+func newHandler(
+	cu usecases.CommentUseCase,
+	uu usecases.UserUseCase,
+	log logger.Logger,
+) *handler {
+	return &handler{
+		commentUseCase: cu,
+		userUseCase:    uu,
+		log:            log,
+	}
+}
+
+func Init(
+	cfg *config.Config,
+	e *echo.Group,
+	cu usecases.CommentUseCase,
+	uu usecases.UserUseCase,
+	log logger.Logger,
+) {
+	h := newHandler(cu, uu, log)
+	auth := middleware.Auth(cfg, uu, log)
+	optionalAuth := middleware.OptionalAuth(cfg, uu, log)
+
+	e.GET("/articles/:id/comments", h.GetAll, optionalAuth)
+	e.POST("/articles/:id/comments", h.Store, auth)
+	e.PUT("/comments/:id", h.Update, auth)
+	e.DELETE("/comments/:id", h.Delete, auth)
+	e.POST("/comments/:id/hide", h.Hide, auth)
+	e.POST("/comments/:id/restore", h.Unhide, auth)
+}
+
+// GetAll godoc
+// @Tags Comments
+// @Summary Get article comments
+// @Description Returns a page of top-level comments with their replies nested under them.
+// @Description Hidden comments are only shown in full to their author and to moderators.
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Param limit query int false "Threads per page"
+// @Param offset query int false "Threads to skip"
+// @Success 200 {object} models.CommentsList
+// @Failure 400,404,500 {object} swagger.Error
+// @Router /articles/{id}/comments [get]
+func (h *handler) GetAll(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	limit, offset, err := utils.GetPagination(c)
+	if err != nil {
+		return echo.ErrBadRequest
+	}
+
+	res, err := h.commentUseCase.GetAll(&models.CommentFilter{
+		ArticleID: id,
+		Limit:     limit,
+		Offset:    offset,
+	}, utils.GetCtxViewerID(c))
+	if err != nil {
+		h.log.Errorf("comment.UseCase.GetAll: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// Store godoc
+// @Tags Comments
+// @Summary Add comment
+// @Description Replies are added by passing the ID of the parent comment.
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID"
+// @Param body body swagger.CommentRequest true "Body"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.Comment
+// @Failure 400,401,403,404,500 {object} swagger.Error
+// @Router /articles/{id}/comments [post]
+func (h *handler) Store(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	comment := new(models.Comment)
+
+	if err := c.Bind(comment); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	comment.ArticleID = id
+	comment.AuthorID = utils.GetCtxID(c)
+
+	createdComment, err := h.commentUseCase.Store(comment)
+	if err != nil {
+		h.log.Errorf("comment.UseCase.Store: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, createdComment)
+}
+
+// Update godoc
+// @Tags Comments
+// @Summary Update comment
+// @Description Comments can only be edited by their author for a limited time after posting.
+// @Accept json
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Param body body swagger.UpdateComment true "Body"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Comment
+// @Failure 400,401,403,404,500 {object} swagger.Error
+// @Router /comments/{id} [put]
+func (h *handler) Update(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	comment := new(models.Comment)
+
+	if err := c.Bind(comment); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	comment.ID = id
+	comment.AuthorID = utils.GetCtxID(c)
+
+	updatedComment, err := h.commentUseCase.Update(comment)
+	if err != nil {
+		h.log.Errorf("comment.UseCase.Update: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, updatedComment)
+}
+
+// Delete godoc
+// @Tags Comments
+// @Summary Delete comment
+// @Description Comments can only be deleted by their author for a limited time after posting.
+// @Accept json
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 400,401,403,404,500 {object} swagger.Error
+// @Router /comments/{id} [delete]
+func (h *handler) Delete(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	if err := h.commentUseCase.Delete(models.Comment{
+		ID:       id,
+		AuthorID: utils.GetCtxID(c),
+	}); err != nil {
+		h.log.Errorf("comment.UseCase.Delete: %v", err)
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Hide godoc
+// @Tags Comments
+// @Summary Hide comment
+// @Description Moderators only.
+// @Accept json
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Comment
+// @Failure 400,401,403,404,500 {object} swagger.Error
+// @Router /comments/{id}/hide [post]
+func (h *handler) Hide(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	hiddenComment, err := h.commentUseCase.Hide(id, utils.GetCtxID(c))
+	if err != nil {
+		h.log.Errorf("comment.UseCase.Hide: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, hiddenComment)
+}
+
+// Unhide godoc
+// @Tags Comments
+// @Summary Restore hidden comment
+// @Description Moderators only.
+// @Accept json
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Comment
+// @Failure 400,401,403,404,500 {object} swagger.Error
+// @Router /comments/{id}/restore [post]
+func (h *handler) Unhide(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	restoredComment, err := h.commentUseCase.Unhide(id, utils.GetCtxID(c))
+	if err != nil {
+		h.log.Errorf("comment.UseCase.Unhide: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, restoredComment)
+}
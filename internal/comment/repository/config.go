@@ -0,0 +1,47 @@
+package repository
+
+// threadRoots limits the top-level comments of an article to the ones that
+// are still worth showing: deleted roots only stay while they have replies.
+const threadRoots = `article_id = $1 AND parent_id IS NULL 
+									AND (deleted_at IS NULL OR EXISTS (
+										SELECT 1 FROM comments r 
+										WHERE r.root_id = comments.id AND r.id <> comments.id 
+										AND r.deleted_at IS NULL
+									))`
+
+var (
+	getCommentQuery = `SELECT * FROM comments 
+									WHERE id = $1 AND deleted_at IS NULL`
+	getCommentsQuery = `WITH roots AS (
+										SELECT id, created_at FROM comments 
+										WHERE ` + threadRoots + ` 
+										ORDER BY created_at LIMIT $2 OFFSET $3
+									) 
+									SELECT c.* FROM comments c 
+									JOIN roots ON roots.id = c.root_id 
+									ORDER BY roots.created_at, roots.id, c.created_at`
+	countCommentsQuery = `SELECT COUNT(*) FROM comments WHERE ` + threadRoots
+	createCommentQuery = `INSERT INTO comments 
+									(id, article_id, author_id, parent_id, root_id, depth, body) 
+									VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *`
+	updateCommentQuery = `UPDATE comments SET body = $1, updated_at = now() 
+									WHERE id = $2 AND author_id = $3 
+									AND deleted_at IS NULL 
+									RETURNING *`
+	deleteCommentQuery = `UPDATE comments SET deleted_at = now() 
+									WHERE id = $1 AND author_id = $2 
+									AND deleted_at IS NULL`
+	hideCommentQuery = `UPDATE comments 
+									SET hidden_at = COALESCE(hidden_at, now()), 
+										hidden_by = COALESCE(hidden_by, $2) 
+									WHERE id = $1 AND deleted_at IS NULL 
+									RETURNING *`
+	unhideCommentQuery = `UPDATE comments SET hidden_at = NULL, hidden_by = NULL 
+									WHERE id = $1 AND deleted_at IS NULL 
+									RETURNING *`
+	purgeCommentsQuery = `DELETE FROM comments 
+									WHERE deleted_at < $1 
+									AND NOT EXISTS (
+										SELECT 1 FROM comments r WHERE r.parent_id = comments.id
+									)`
+)
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/pkg/store/postgres"
+)
+
+type pgRepository struct {
+	db postgres.DB
+}
+
+func NewPGRepository(db postgres.DB) repositories.PGCommentRepository {
+	return &pgRepository{db}
+}
+
+func (r *pgRepository) GetAll(
+	filter *models.CommentFilter,
+) ([]models.Comment, int, error) {
+	var (
+		comments []models.Comment
+		total    int
+	)
+
+	if err := r.db.Get(
+		&total,
+		countCommentsQuery,
+		filter.ArticleID,
+	); err != nil {
+		return comments, total, echo.ErrInternalServerError
+	}
+
+	if err := r.db.Select(
+		&comments,
+		getCommentsQuery,
+		filter.ArticleID,
+		filter.Limit,
+		filter.Offset,
+	); err != nil {
+		return comments, total, echo.ErrInternalServerError
+	}
+
+	return comments, total, nil
+}
+
+func (r *pgRepository) GetByID(id uuid.UUID) (models.Comment, error) {
+	var comment models.Comment
+
+	if err := r.db.Get(
+		&comment,
+		getCommentQuery,
+		id,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return comment, echo.ErrNotFound
+		}
+
+		return comment, echo.ErrBadRequest
+	}
+
+	return comment, nil
+}
+
+func (r *pgRepository) Store(c *models.Comment) (*models.Comment, error) {
+	var comment models.Comment
+
+	id := uuid.New()
+	rootID := c.RootID
+	if c.ParentID == nil {
+		rootID = id
+	}
+
+	if err := r.db.QueryRowx(
+		createCommentQuery,
+		id,
+		c.ArticleID,
+		c.AuthorID,
+		c.ParentID,
+		rootID,
+		c.Depth,
+		c.Body,
+	).StructScan(&comment); err != nil {
+		return nil, echo.ErrBadRequest
+	}
+
+	return &comment, nil
+}
+
+func (r *pgRepository) Update(c *models.Comment) (*models.Comment, error) {
+	var comment models.Comment
+
+	if err := r.db.QueryRowx(
+		updateCommentQuery,
+		c.Body,
+		c.ID,
+		c.AuthorID,
+	).StructScan(&comment); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, echo.ErrNotFound
+		}
+
+		return nil, echo.ErrBadRequest
+	}
+
+	return &comment, nil
+}
+
+func (r *pgRepository) Delete(c models.Comment) error {
+	res, err := r.db.Exec(
+		deleteCommentQuery,
+		c.ID,
+		c.AuthorID,
+	)
+	if err != nil {
+		return echo.ErrBadRequest
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	if rowsAffected == 0 {
+		return echo.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *pgRepository) Hide(
+	id uuid.UUID,
+	moderatorID uuid.UUID,
+) (*models.Comment, error) {
+	return r.moderate(hideCommentQuery, id, moderatorID)
+}
+
+func (r *pgRepository) Unhide(id uuid.UUID) (*models.Comment, error) {
+	return r.moderate(unhideCommentQuery, id)
+}
+
+func (r *pgRepository) Purge(before time.Time) (int64, error) {
+	res, err := r.db.Exec(purgeCommentsQuery, before)
+	if err != nil {
+		return 0, echo.ErrInternalServerError
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, echo.ErrInternalServerError
+	}
+
+	return rowsAffected, nil
+}
+
+func (r *pgRepository) moderate(
+	query string,
+	args ...interface{},
+) (*models.Comment, error) {
+	var comment models.Comment
+
+	if err := r.db.QueryRowx(
+		query,
+		args...,
+	).StructScan(&comment); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, echo.ErrNotFound
+		}
+
+		return nil, echo.ErrBadRequest
+	}
+
+	return &comment, nil
+}
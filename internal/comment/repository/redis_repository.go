@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/pkg/store/redis"
+	"github.com/slavtov/clean-architecture/pkg/utils"
+)
+
+type redisRepository struct {
+	redis redis.Store
+}
+
+const (
+	prefix        = "comments"
+	articlePrefix = "articles"
+)
+
+func NewRedisRepository(rdb redis.Store) repositories.RedisCommentRepository {
+	return &redisRepository{rdb}
+}
+
+func (r *redisRepository) GetAll(
+	filter *models.CommentFilter,
+) (models.CommentsList, error) {
+	var list models.CommentsList
+
+	res, err := r.redis.Get(getKey(filter))
+	if err != nil {
+		return list, echo.ErrNotFound
+	}
+
+	if err := json.Unmarshal([]byte(res), &list); err != nil {
+		return list, echo.ErrInternalServerError
+	}
+
+	return list, nil
+}
+
+func (r *redisRepository) SetComments(
+	filter *models.CommentFilter,
+	list *models.CommentsList,
+	exp time.Duration,
+) error {
+	res, err := json.Marshal(list)
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	if err := r.redis.Set(getKey(filter), res, exp); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (r *redisRepository) DeleteAll(articleID uuid.UUID) error {
+	if err := r.redis.DelAll(utils.GetRedisKey(
+		prefix,
+		articlePrefix,
+		articleID.String(),
+		"*",
+	)); err != nil {
+		return echo.ErrNotFound
+	}
+
+	return nil
+}
+
+// getKey returns the key of a cached page of threads, e.g.
+// comments:articles:<article id>:<limit>:<offset>.
+func getKey(filter *models.CommentFilter) string {
+	return utils.GetRedisKey(
+		prefix,
+		articlePrefix,
+		filter.ArticleID.String(),
+		strconv.Itoa(filter.Limit),
+		strconv.Itoa(filter.Offset),
+	)
+}
@@ -0,0 +1,311 @@
+package usecase
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/config"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/internal/domain/usecases"
+	"github.com/slavtov/clean-architecture/pkg/logger"
+)
+
+type usecase struct {
+	cfg             *config.Config
+	pgRepository    repositories.PGCommentRepository
+	redisRepository repositories.RedisCommentRepository
+	articleUseCase  usecases.ArticleUseCase
+	userUseCase     usecases.UserUseCase
+	log             logger.Logger
+}
+
+const (
+	cacheDuration = 3600
+	maxPageSize   = 100
+)
+
+var (
+	errCommentsClosed = echo.NewHTTPError(
+		http.StatusForbidden,
+		"article is not open for comments",
+	)
+	errEditWindow = echo.NewHTTPError(
+		http.StatusForbidden,
+		"comment can no longer be changed",
+	)
+)
+
+func New(
+	cfg *config.Config,
+	pg repositories.PGCommentRepository,
+	redis repositories.RedisCommentRepository,
+	au usecases.ArticleUseCase,
+	uu usecases.UserUseCase,
+	log logger.Logger,
+) usecases.CommentUseCase {
+	return &usecase{
+		cfg:             cfg,
+		pgRepository:    pg,
+		redisRepository: redis,
+		articleUseCase:  au,
+		userUseCase:     uu,
+		log:             log,
+	}
+}
+
+func (u *usecase) GetAll(
+	filter *models.CommentFilter,
+	viewerID uuid.UUID,
+) (models.CommentsList, error) {
+	if _, err := u.articleUseCase.GetByID(
+		filter.ArticleID,
+		viewerID,
+	); err != nil {
+		return models.CommentsList{}, err
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = u.cfg.Comments.PageSize
+	}
+
+	if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	res, err := u.getAll(filter)
+	if err != nil {
+		return res, err
+	}
+
+	moderator := u.isModerator(viewerID)
+	for i := range res.Comments {
+		res.Comments[i].Redact(viewerID, moderator)
+	}
+
+	return res, nil
+}
+
+func (u *usecase) getAll(
+	filter *models.CommentFilter,
+) (models.CommentsList, error) {
+	cachedComments, err := u.redisRepository.GetAll(filter)
+	if err != nil {
+		u.log.Errorf("comment.redisRepository.GetAll: %v", err)
+	}
+
+	if cachedComments.Comments != nil {
+		return cachedComments, nil
+	}
+
+	comments, total, err := u.pgRepository.GetAll(filter)
+	if err != nil {
+		u.log.Errorf("comment.pgRepository.GetAll: %v", err)
+		return models.CommentsList{}, err
+	}
+
+	res := models.CommentsList{
+		TotalCount: total,
+		Comments:   models.NewCommentThreads(comments),
+	}
+
+	if err := u.redisRepository.SetComments(
+		filter,
+		&res,
+		time.Second*cacheDuration,
+	); err != nil {
+		u.log.Errorf("comment.redisRepository.SetComments: %v", err)
+	}
+
+	return res, nil
+}
+
+func (u *usecase) Store(comment *models.Comment) (*models.Comment, error) {
+	if err := comment.Validate(); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	article, err := u.articleUseCase.GetByID(comment.ArticleID, comment.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if article.Status != models.ArticlePublished {
+		return nil, errCommentsClosed
+	}
+
+	comment.Depth = 0
+	if comment.ParentID != nil {
+		parent, err := u.pgRepository.GetByID(*comment.ParentID)
+		if err != nil {
+			u.log.Errorf("comment.pgRepository.GetByID: %v", err)
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "parent comment not found")
+		}
+
+		if parent.ArticleID != comment.ArticleID {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "parent comment not found")
+		}
+
+		if parent.Depth+1 > u.cfg.Comments.MaxDepth {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "thread is too deep")
+		}
+
+		comment.RootID = parent.RootID
+		comment.Depth = parent.Depth + 1
+	}
+
+	res, err := u.pgRepository.Store(comment)
+	if err != nil {
+		u.log.Errorf("comment.pgRepository.Store: %v", err)
+		return nil, err
+	}
+
+	u.invalidate(res.ArticleID)
+
+	return res, nil
+}
+
+func (u *usecase) Update(comment *models.Comment) (*models.Comment, error) {
+	if err := comment.Validate(); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if _, err := u.getOwn(
+		comment,
+		time.Second*time.Duration(u.cfg.Comments.EditWindow),
+	); err != nil {
+		return nil, err
+	}
+
+	res, err := u.pgRepository.Update(comment)
+	if err != nil {
+		u.log.Errorf("comment.pgRepository.Update: %v", err)
+		return nil, err
+	}
+
+	u.invalidate(res.ArticleID)
+
+	return res, nil
+}
+
+func (u *usecase) Delete(comment models.Comment) error {
+	current, err := u.getOwn(
+		&comment,
+		time.Second*time.Duration(u.cfg.Comments.DeleteWindow),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := u.pgRepository.Delete(comment); err != nil {
+		u.log.Errorf("comment.pgRepository.Delete: %v", err)
+		return err
+	}
+
+	u.invalidate(current.ArticleID)
+
+	return nil
+}
+
+func (u *usecase) Hide(
+	id uuid.UUID,
+	moderatorID uuid.UUID,
+) (*models.Comment, error) {
+	if !u.isModerator(moderatorID) {
+		return nil, echo.ErrForbidden
+	}
+
+	res, err := u.pgRepository.Hide(id, moderatorID)
+	if err != nil {
+		u.log.Errorf("comment.pgRepository.Hide: %v", err)
+		return nil, err
+	}
+
+	u.invalidate(res.ArticleID)
+
+	return res, nil
+}
+
+func (u *usecase) Unhide(
+	id uuid.UUID,
+	moderatorID uuid.UUID,
+) (*models.Comment, error) {
+	if !u.isModerator(moderatorID) {
+		return nil, echo.ErrForbidden
+	}
+
+	res, err := u.pgRepository.Unhide(id)
+	if err != nil {
+		u.log.Errorf("comment.pgRepository.Unhide: %v", err)
+		return nil, err
+	}
+
+	u.invalidate(res.ArticleID)
+
+	return res, nil
+}
+
+func (u *usecase) Purge(before time.Time) (int64, error) {
+	n, err := u.pgRepository.Purge(before)
+	if err != nil {
+		u.log.Errorf("comment.pgRepository.Purge: %v", err)
+		return 0, err
+	}
+
+	if n > 0 {
+		u.log.Infof("comment.Purge: %d comments purged", n)
+	}
+
+	return n, nil
+}
+
+// getOwn returns the current state of the comment after checking that it
+// belongs to the caller, is not hidden by a moderator and is younger than
+// window.
+func (u *usecase) getOwn(
+	comment *models.Comment,
+	window time.Duration,
+) (models.Comment, error) {
+	current, err := u.pgRepository.GetByID(comment.ID)
+	if err != nil {
+		u.log.Errorf("comment.pgRepository.GetByID: %v", err)
+		return current, err
+	}
+
+	if current.AuthorID != comment.AuthorID {
+		return current, echo.ErrNotFound
+	}
+
+	if current.HiddenAt != nil ||
+		!current.IsEditableBy(comment.AuthorID, window, time.Now()) {
+		return current, errEditWindow
+	}
+
+	return current, nil
+}
+
+func (u *usecase) isModerator(id uuid.UUID) bool {
+	if id == uuid.Nil {
+		return false
+	}
+
+	user, err := u.userUseCase.GetByID(id)
+	if err != nil {
+		u.log.Errorf("auth.UseCase.GetByID: %v", err)
+		return false
+	}
+
+	return user.IsModerator()
+}
+
+func (u *usecase) invalidate(articleID uuid.UUID) {
+	if err := u.redisRepository.DeleteAll(articleID); err != nil {
+		u.log.Errorf("comment.redisRepository.DeleteAll: %v", err)
+	}
+}
@@ -8,29 +8,49 @@ import (
 
 type (
 	Config struct {
-		Server ServerConfig
-		DB     DBConfig
-		Redis  RedisConfig
-		Cookie CookieConfig
-		Logger Logger
+		Server     ServerConfig
+		DB         DBConfig
+		Redis      RedisConfig
+		Cookie     CookieConfig
+		SoftDelete SoftDeleteConfig `mapstructure:"soft_delete"`
+		HTTPCache  HTTPCacheConfig  `mapstructure:"http_cache"`
+		Scheduler  SchedulerConfig
+		Articles   ArticlesConfig
+		Comments   CommentsConfig
+		Uploads    UploadsConfig
+		Blob       BlobConfig
+		Mailer     MailerConfig
+		Account    AccountConfig
+		Password   PasswordConfig
+		Outbox     OutboxConfig
+		Webhooks   WebhooksConfig
+		Feed       FeedConfig
+		GraphQL    GraphQLConfig
+		Logger     Logger
 	}
 
 	ServerConfig struct {
 		Debug            bool
 		AppVersion       string `mapstructure:"app_version"`
 		Addr             string
+		GrpcAddr         string `mapstructure:"grpc_addr"`
+		GrpcReflection   bool   `mapstructure:"grpc_reflection"`
+		ShutdownTimeout  int    `mapstructure:"shutdown_timeout"`
+		BaseURL          string `mapstructure:"base_url"`
+		TrustProxy       bool   `mapstructure:"trust_proxy"`
 		JwtSecret        string `mapstructure:"jwt_secret"`
 		JwtRefreshSecret string `mapstructure:"jwt_refresh_secret"`
 	}
 
 	DBConfig struct {
-		Driver   string
-		Host     string
-		Port     int
-		User     string
-		Password string
-		Name     string
-		SSL      string
+		Driver      string
+		Host        string
+		Port        int
+		User        string
+		Password    string
+		Name        string
+		SSL         string
+		AutoMigrate bool `mapstructure:"auto_migrate"`
 	}
 
 	RedisConfig struct {
@@ -53,6 +73,139 @@ type (
 		RefreshToken TokenConfig `mapstructure:"refresh_token"`
 	}
 
+	SoftDeleteConfig struct {
+		Retention     int
+		PurgeInterval int `mapstructure:"purge_interval"`
+	}
+
+	HTTPCacheConfig struct {
+		CacheControl string `mapstructure:"cache_control"`
+	}
+
+	SchedulerConfig struct {
+		PublishInterval  int `mapstructure:"publish_interval"`
+		PublishBatchSize int `mapstructure:"publish_batch_size"`
+		LikesInterval    int `mapstructure:"likes_interval"`
+	}
+
+	ArticlesConfig struct {
+		BulkLimit int `mapstructure:"bulk_limit"`
+	}
+
+	CommentsConfig struct {
+		EditWindow   int `mapstructure:"edit_window"`
+		DeleteWindow int `mapstructure:"delete_window"`
+		MaxDepth     int `mapstructure:"max_depth"`
+		PageSize     int `mapstructure:"page_size"`
+	}
+
+	UploadsConfig struct {
+		MaxSize      int64    `mapstructure:"max_size"`
+		MaxWidth     int      `mapstructure:"max_width"`
+		MaxHeight    int      `mapstructure:"max_height"`
+		AllowedTypes []string `mapstructure:"allowed_types"`
+		CacheControl string   `mapstructure:"cache_control"`
+	}
+
+	BlobConfig struct {
+		Driver string
+		Path   string
+		S3     S3Config
+	}
+
+	S3Config struct {
+		Endpoint  string
+		AccessKey string `mapstructure:"access_key"`
+		SecretKey string `mapstructure:"secret_key"`
+		Bucket    string
+		Region    string
+		SSL       bool
+	}
+
+	MailerConfig struct {
+		Driver   string
+		Host     string
+		Port     int
+		Username string
+		Password string
+		From     string
+	}
+
+	AccountConfig struct {
+		EmailChangeTTL      int `mapstructure:"email_change_ttl"`
+		DeletionGracePeriod int `mapstructure:"deletion_grace_period"`
+	}
+
+	PasswordConfig struct {
+		MinLength     int    `mapstructure:"min_length"`
+		MaxLength     int    `mapstructure:"max_length"`
+		RequireUpper  bool   `mapstructure:"require_upper"`
+		RequireLower  bool   `mapstructure:"require_lower"`
+		RequireDigit  bool   `mapstructure:"require_digit"`
+		RequireSymbol bool   `mapstructure:"require_symbol"`
+		BreachedPath  string `mapstructure:"breached_path"`
+		Algorithm     string
+		BcryptCost    int `mapstructure:"bcrypt_cost"`
+		Argon2        Argon2Config
+	}
+
+	Argon2Config struct {
+		Memory      uint32
+		Iterations  uint32
+		Parallelism uint8
+	}
+
+	OutboxConfig struct {
+		Sink            string
+		Interval        int
+		BatchSize       int `mapstructure:"batch_size"`
+		Lease           int
+		MaxAttempts     int `mapstructure:"max_attempts"`
+		RetryBackoff    int `mapstructure:"retry_backoff"`
+		MaxRetryBackoff int `mapstructure:"max_retry_backoff"`
+		Retention       int
+		Redis           OutboxRedisConfig
+		Webhook         OutboxWebhookConfig
+	}
+
+	OutboxRedisConfig struct {
+		Stream string
+		MaxLen int64 `mapstructure:"max_len"`
+	}
+
+	OutboxWebhookConfig struct {
+		URL     string
+		Secret  string
+		Timeout int
+	}
+
+	WebhooksConfig struct {
+		Interval        int
+		BatchSize       int `mapstructure:"batch_size"`
+		Lease           int
+		MaxAttempts     int `mapstructure:"max_attempts"`
+		RetryBackoff    int `mapstructure:"retry_backoff"`
+		MaxRetryBackoff int `mapstructure:"max_retry_backoff"`
+		Timeout         int
+		MaxPerUser      int  `mapstructure:"max_per_user"`
+		AllowPrivate    bool `mapstructure:"allow_private"`
+		LogSize         int  `mapstructure:"log_size"`
+		Retention       int
+	}
+
+	FeedConfig struct {
+		MaxLen         int64 `mapstructure:"max_len"`
+		ReplayLimit    int   `mapstructure:"replay_limit"`
+		Heartbeat      int
+		BufferSize     int      `mapstructure:"buffer_size"`
+		AllowedOrigins []string `mapstructure:"allowed_origins"`
+	}
+
+	GraphQLConfig struct {
+		MaxDepth      int `mapstructure:"max_depth"`
+		MaxComplexity int `mapstructure:"max_complexity"`
+	}
+
 	Logger struct {
 		Level string
 	}
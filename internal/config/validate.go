@@ -0,0 +1,110 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Validate reports every setting that would keep the application from
+// starting or working correctly.
+func (c *Config) Validate() error {
+	var errs []string
+
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(c.Server.Addr != "", "server.addr is required")
+	check(
+		c.Server.GrpcAddr == "" || c.Server.GrpcAddr != c.Server.Addr,
+		"server.grpc_addr and server.addr must differ",
+	)
+	check(c.Server.ShutdownTimeout >= 0, "server.shutdown_timeout must not be negative")
+	check(c.Server.JwtSecret != "", "server.jwt_secret is required")
+	check(c.Server.JwtRefreshSecret != "", "server.jwt_refresh_secret is required")
+	check(
+		c.Server.JwtSecret == "" || c.Server.JwtSecret != c.Server.JwtRefreshSecret,
+		"server.jwt_secret and server.jwt_refresh_secret must differ",
+	)
+
+	check(c.DB.Driver != "", "db.driver is required")
+	check(c.DB.Name != "", "db.name is required")
+	check(c.Redis.Addr != "", "redis.addr is required")
+
+	check(c.Cookie.AccessToken.MaxAge > 0, "cookie.access_token.max_age must be positive")
+	check(c.Cookie.RefreshToken.MaxAge > 0, "cookie.refresh_token.max_age must be positive")
+
+	check(
+		c.Blob.Driver == "local" || c.Blob.Driver == "s3",
+		"blob.driver must be local or s3, got %q", c.Blob.Driver,
+	)
+	check(c.Blob.Driver != "local" || c.Blob.Path != "", "blob.path is required for the local driver")
+	check(c.Blob.Driver != "s3" || c.Blob.S3.Bucket != "", "blob.s3.bucket is required for the s3 driver")
+
+	check(
+		c.Mailer.Driver == "log" || c.Mailer.Driver == "smtp",
+		"mailer.driver must be log or smtp, got %q", c.Mailer.Driver,
+	)
+	check(c.Mailer.Driver != "smtp" || c.Mailer.Host != "", "mailer.host is required for the smtp driver")
+	check(c.Mailer.From != "", "mailer.from is required")
+
+	check(
+		c.Password.Algorithm == "argon2id" || c.Password.Algorithm == "bcrypt",
+		"password.algorithm must be argon2id or bcrypt, got %q", c.Password.Algorithm,
+	)
+	check(
+		c.Password.MaxLength == 0 || c.Password.MinLength <= c.Password.MaxLength,
+		"password.min_length must not exceed password.max_length",
+	)
+	check(
+		c.Password.Algorithm != "argon2id" ||
+			(c.Password.Argon2.Memory > 0 && c.Password.Argon2.Iterations > 0 && c.Password.Argon2.Parallelism > 0),
+		"password.argon2 memory, iterations and parallelism must be positive",
+	)
+	check(
+		c.Password.Algorithm != "bcrypt" || (c.Password.BcryptCost >= 4 && c.Password.BcryptCost <= 31),
+		"password.bcrypt_cost must be between 4 and 31",
+	)
+
+	check(
+		c.Outbox.Sink == "log" || c.Outbox.Sink == "redis" || c.Outbox.Sink == "webhook",
+		"outbox.sink must be log, redis or webhook, got %q", c.Outbox.Sink,
+	)
+	check(c.Outbox.Sink != "redis" || c.Outbox.Redis.Stream != "", "outbox.redis.stream is required for the redis sink")
+	check(c.Outbox.Sink != "webhook" || c.Outbox.Webhook.URL != "", "outbox.webhook.url is required for the webhook sink")
+	check(c.Outbox.BatchSize > 0, "outbox.batch_size must be positive")
+	check(c.Outbox.Lease > 0, "outbox.lease must be positive")
+	check(
+		c.Outbox.Sink != "webhook" || c.Outbox.Lease > c.Outbox.BatchSize*c.Outbox.Webhook.Timeout,
+		"outbox.lease must exceed outbox.batch_size times outbox.webhook.timeout",
+	)
+	check(c.Outbox.MaxAttempts > 0, "outbox.max_attempts must be positive")
+
+	check(c.Webhooks.BatchSize > 0, "webhooks.batch_size must be positive")
+	check(c.Webhooks.MaxAttempts > 0, "webhooks.max_attempts must be positive")
+	check(c.Webhooks.Timeout > 0, "webhooks.timeout must be positive")
+	check(
+		c.Webhooks.Lease > c.Webhooks.BatchSize*c.Webhooks.Timeout,
+		"webhooks.lease must exceed webhooks.batch_size times webhooks.timeout",
+	)
+
+	check(c.Feed.Heartbeat > 0, "feed.heartbeat must be positive")
+	check(c.Feed.ReplayLimit > 0, "feed.replay_limit must be positive")
+	check(c.GraphQL.MaxDepth > 0, "graphql.max_depth must be positive")
+	check(c.GraphQL.MaxComplexity > 0, "graphql.max_complexity must be positive")
+
+	check(c.Uploads.MaxSize > 0, "uploads.max_size must be positive")
+	check(c.Articles.BulkLimit > 0, "articles.bulk_limit must be positive")
+	// A bulk insert binds 8 parameters per article and Postgres allows 65535.
+	check(c.Articles.BulkLimit <= 8000, "articles.bulk_limit must be at most 8000")
+	check(c.Comments.PageSize > 0, "comments.page_size must be positive")
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+
+	return nil
+}
@@ -1,20 +1,57 @@
 package models
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+type ArticleStatus string
+
+const (
+	ArticleDraft     ArticleStatus = "draft"
+	ArticleScheduled ArticleStatus = "scheduled"
+	ArticlePublished ArticleStatus = "published"
+	ArticleArchived  ArticleStatus = "archived"
+)
+
+type ArticleFormat string
+
+const (
+	ArticlePlain    ArticleFormat = "plain"
+	ArticleMarkdown ArticleFormat = "markdown"
+)
+
+var articleTransitions = map[ArticleStatus][]ArticleStatus{
+	ArticleDraft:     {ArticleScheduled, ArticlePublished},
+	ArticleScheduled: {ArticleDraft, ArticlePublished},
+	ArticlePublished: {ArticleDraft, ArticleArchived},
+	ArticleArchived:  {ArticleDraft, ArticlePublished},
+}
+
 type Article struct {
-	ID        uuid.UUID `json:"id" db:"id" example:"00000000-0000-0000-0000-000000000000"`
-	AuthorID  uuid.UUID `json:"author_id" db:"author_id" validate:"required" example:"00000000-0000-0000-0000-000000000000"`
-	Title     string    `json:"title" db:"title" validate:"required,min=5,max=250" example:"Title"`
-	Desc      string    `json:"desc" db:"desc" validate:"required" example:"Description"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at" example:"0000-01-01T00:00:00.000000Z"`
-	CreatedAt time.Time `json:"created_at" db:"created_at" example:"0000-01-01T00:00:00.000000Z"`
+	ID          uuid.UUID      `json:"id" db:"id" example:"00000000-0000-0000-0000-000000000000"`
+	AuthorID    uuid.UUID      `json:"author_id" db:"author_id" validate:"required" example:"00000000-0000-0000-0000-000000000000"`
+	Author      *UserSummary   `json:"author,omitempty" db:"-"`
+	Title       string         `json:"title" db:"title" validate:"required,min=5,max=250" example:"Title"`
+	Slug        string         `json:"slug" db:"slug" example:"title"`
+	Desc        string         `json:"desc" db:"desc" validate:"required" example:"Description"`
+	DescHTML    string         `json:"desc_html" db:"-" example:"<p>Description</p>"`
+	Format      ArticleFormat  `json:"format" db:"format" validate:"omitempty,oneof=plain markdown" example:"plain"`
+	Status      ArticleStatus  `json:"status" db:"status" validate:"omitempty,oneof=draft scheduled published archived" example:"draft"`
+	PublishedAt *time.Time     `json:"published_at" db:"published_at" example:"0000-01-01T00:00:00.000000Z"`
+	Tags        pq.StringArray `json:"tags" db:"tags" validate:"max=10,dive,max=50" swaggertype:"array,string" example:"golang"`
+	LikesCount  int            `json:"likes_count" db:"likes_count" example:"0"`
+	Liked       *bool          `json:"liked,omitempty" db:"-" example:"false"`
+	Bookmarked  *bool          `json:"bookmarked,omitempty" db:"-" example:"false"`
+	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at" example:"0000-01-01T00:00:00.000000Z"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at" example:"0000-01-01T00:00:00.000000Z"`
+	DeletedAt   *time.Time     `json:"-" db:"deleted_at"`
 }
 
 type ArticlesList struct {
@@ -22,11 +59,113 @@ type ArticlesList struct {
 	Articles   []Article `json:"articles"`
 }
 
+// ArticleBulkResult is the outcome of one item of a bulk request. Index
+// is the position of the item in the request and Status the HTTP status
+// the single-item endpoint would have answered with.
+type ArticleBulkResult struct {
+	Index   int        `json:"index" example:"0"`
+	ID      *uuid.UUID `json:"id,omitempty" example:"00000000-0000-0000-0000-000000000000"`
+	Status  int        `json:"status" example:"201"`
+	Error   string     `json:"error,omitempty" example:"title is required"`
+	Article *Article   `json:"article,omitempty"`
+}
+
+type ArticleBulkResults struct {
+	Succeeded int                 `json:"succeeded" example:"1"`
+	Failed    int                 `json:"failed" example:"0"`
+	Results   []ArticleBulkResult `json:"results"`
+}
+
+// ArticleReactions is the state of an article for a single user.
+type ArticleReactions struct {
+	ArticleID  uuid.UUID `db:"article_id"`
+	Liked      bool      `db:"liked"`
+	Bookmarked bool      `db:"bookmarked"`
+}
+
+type ArticleFilter struct {
+	ViewerID uuid.UUID
+	AuthorID *uuid.UUID
+	Tags     []string
+}
+
 func (a *Article) Validate() error {
 	validate := validator.New()
 
 	a.Title = strings.TrimSpace(a.Title)
 	a.Desc = strings.TrimSpace(a.Desc)
+	a.Tags = NormalizeTags(a.Tags)
 
 	return validate.Struct(a)
 }
+
+// IsVisibleTo reports whether the article can be read by the viewer.
+// Only published articles are public, everything else is author-only.
+func (a *Article) IsVisibleTo(viewerID uuid.UUID) bool {
+	return a.Status == ArticlePublished || a.AuthorID == viewerID
+}
+
+// ApplyStatus checks the transition from the current state of the article
+// (nil for a new one) to the requested status and sets the publication
+// time that goes with it. An empty status keeps the current one.
+//
+// A scheduled article whose publication time has passed counts as
+// published even if the scheduler has not got to it yet, and keeping it
+// scheduled at that time publishes it.
+func (a *Article) ApplyStatus(current *Article, now time.Time) error {
+	from := ArticleDraft
+	if current != nil {
+		from = current.Status
+	}
+
+	if from == ArticleScheduled && current.PublishedAt != nil &&
+		!current.PublishedAt.After(now) {
+		from = ArticlePublished
+
+		if a.Status == ArticleScheduled &&
+			(a.PublishedAt == nil || a.PublishedAt.Equal(*current.PublishedAt)) {
+			a.Status = ArticlePublished
+		}
+	}
+
+	if a.Status == "" {
+		a.Status = from
+	}
+
+	if a.Status != from && !canTransition(from, a.Status) {
+		return fmt.Errorf("cannot change status from %s to %s", from, a.Status)
+	}
+
+	switch a.Status {
+	case ArticleDraft:
+		a.PublishedAt = nil
+	case ArticleScheduled:
+		if a.PublishedAt == nil && current != nil {
+			a.PublishedAt = current.PublishedAt
+		}
+
+		if a.PublishedAt == nil || !a.PublishedAt.After(now) {
+			return errors.New("published_at must be in the future")
+		}
+	case ArticlePublished:
+		if from != ArticlePublished {
+			a.PublishedAt = &now
+		} else {
+			a.PublishedAt = current.PublishedAt
+		}
+	case ArticleArchived:
+		a.PublishedAt = current.PublishedAt
+	}
+
+	return nil
+}
+
+func canTransition(from, to ArticleStatus) bool {
+	for _, status := range articleTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type (
+	Attachment struct {
+		ID          uuid.UUID `json:"id" db:"id" example:"00000000-0000-0000-0000-000000000000"`
+		ArticleID   uuid.UUID `json:"article_id" db:"article_id" example:"00000000-0000-0000-0000-000000000000"`
+		UploaderID  uuid.UUID `json:"uploader_id" db:"uploader_id" example:"00000000-0000-0000-0000-000000000000"`
+		Key         string    `json:"-" db:"key"`
+		Filename    string    `json:"filename" db:"filename" validate:"required,max=255" example:"image.png"`
+		ContentType string    `json:"content_type" db:"content_type" example:"image/png"`
+		Size        int64     `json:"size" db:"size" example:"1024"`
+		Width       *int      `json:"width,omitempty" db:"width" example:"800"`
+		Height      *int      `json:"height,omitempty" db:"height" example:"600"`
+		Checksum    string    `json:"checksum" db:"checksum" example:"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"`
+		URL         string    `json:"url" db:"-" example:"/api/attachments/00000000-0000-0000-0000-000000000000"`
+		Public      bool      `json:"-" db:"-"`
+		CreatedAt   time.Time `json:"created_at" db:"created_at" example:"0000-01-01T00:00:00.000000Z"`
+	}
+
+	AttachmentsList struct {
+		TotalCount  int          `json:"total_count"`
+		Attachments []Attachment `json:"attachments"`
+	}
+)
+
+func (a *Attachment) Validate() error {
+	validate := validator.New()
+
+	// Some clients send the full path of the file.
+	a.Filename = path.Base(strings.ReplaceAll(a.Filename, `\`, "/"))
+	a.Filename = strings.TrimSpace(a.Filename)
+	if a.Filename == "." || a.Filename == "/" {
+		a.Filename = ""
+	}
+
+	return validate.Struct(a)
+}
+
+func (a *Attachment) IsImage() bool {
+	return strings.HasPrefix(a.ContentType, "image/")
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type (
+	Comment struct {
+		ID        uuid.UUID  `json:"id" db:"id" example:"00000000-0000-0000-0000-000000000000"`
+		ArticleID uuid.UUID  `json:"article_id" db:"article_id" example:"00000000-0000-0000-0000-000000000000"`
+		AuthorID  uuid.UUID  `json:"author_id" db:"author_id" example:"00000000-0000-0000-0000-000000000000"`
+		ParentID  *uuid.UUID `json:"parent_id" db:"parent_id" example:"00000000-0000-0000-0000-000000000000"`
+		RootID    uuid.UUID  `json:"-" db:"root_id"`
+		Depth     int        `json:"depth" db:"depth" example:"0"`
+		Body      string     `json:"body" db:"body" validate:"required,max=5000" example:"Comment"`
+		HiddenAt  *time.Time `json:"hidden_at,omitempty" db:"hidden_at" example:"0000-01-01T00:00:00.000000Z"`
+		HiddenBy  *uuid.UUID `json:"hidden_by,omitempty" db:"hidden_by" example:"00000000-0000-0000-0000-000000000000"`
+		UpdatedAt time.Time  `json:"updated_at" db:"updated_at" example:"0000-01-01T00:00:00.000000Z"`
+		CreatedAt time.Time  `json:"created_at" db:"created_at" example:"0000-01-01T00:00:00.000000Z"`
+		DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at" example:"0000-01-01T00:00:00.000000Z"`
+		Replies   []Comment  `json:"replies" db:"-"`
+	}
+
+	CommentsList struct {
+		TotalCount int       `json:"total_count"`
+		Comments   []Comment `json:"comments"`
+	}
+
+	CommentFilter struct {
+		ArticleID uuid.UUID
+		Limit     int
+		Offset    int
+	}
+)
+
+func (c *Comment) Validate() error {
+	validate := validator.New()
+
+	c.Body = strings.TrimSpace(c.Body)
+
+	return validate.Struct(c)
+}
+
+// IsEditableBy reports whether the author can still change the comment,
+// i.e. it is theirs and was posted less than window ago.
+func (c *Comment) IsEditableBy(authorID uuid.UUID, window time.Duration, now time.Time) bool {
+	return c.AuthorID == authorID && now.Sub(c.CreatedAt) < window
+}
+
+// Redact blanks out deleted comments and, unless the viewer is the author
+// or a moderator, hidden ones. Redacted comments stay in the thread so
+// that their replies keep their place.
+func (c *Comment) Redact(viewerID uuid.UUID, moderator bool) {
+	if !moderator {
+		c.HiddenBy = nil
+	}
+
+	switch {
+	case c.DeletedAt != nil:
+		c.Body = ""
+	case c.HiddenAt != nil && !moderator && c.AuthorID != viewerID:
+		c.Body = ""
+	}
+
+	for i := range c.Replies {
+		c.Replies[i].Redact(viewerID, moderator)
+	}
+}
+
+// NewCommentThreads nests the comments under their parents, keeping the
+// order of the input, and drops deleted comments left without replies.
+// Comments whose parent is not in the input become roots.
+func NewCommentThreads(comments []Comment) []Comment {
+	children := make(map[uuid.UUID][]int, len(comments))
+	ids := make(map[uuid.UUID]bool, len(comments))
+	for _, c := range comments {
+		ids[c.ID] = true
+	}
+
+	var roots []int
+	for i, c := range comments {
+		if c.ParentID != nil && ids[*c.ParentID] {
+			children[*c.ParentID] = append(children[*c.ParentID], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+
+	var build func(indexes []int) []Comment
+	build = func(indexes []int) []Comment {
+		res := make([]Comment, 0, len(indexes))
+		for _, i := range indexes {
+			c := comments[i]
+			c.Replies = build(children[c.ID])
+
+			if c.DeletedAt != nil && len(c.Replies) == 0 {
+				continue
+			}
+
+			res = append(res, c)
+		}
+
+		return res
+	}
+
+	return build(roots)
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+)
+
+// Domain events written to the outbox.
+const (
+	EventArticleCreated = "article.created"
+	EventArticleUpdated = "article.updated"
+	EventArticleDeleted = "article.deleted"
+	EventUserRegistered = "user.registered"
+	EventUserDeleted    = "user.deleted"
+)
+
+// Delivery states of an outbox event. Events that keep failing end up
+// dead and are no longer retried until they are requeued.
+const (
+	EventPending   = "pending"
+	EventPublished = "published"
+	EventDead      = "dead"
+)
+
+type (
+	Event struct {
+		ID          int64          `json:"id" db:"id" example:"1"`
+		Type        string         `json:"type" db:"type" example:"article.created"`
+		AggregateID uuid.UUID      `json:"aggregate_id" db:"aggregate_id" example:"00000000-0000-0000-0000-000000000000"`
+		Payload     types.JSONText `json:"payload" db:"payload" swaggertype:"object"`
+		Status      string         `json:"-" db:"status"`
+		Attempts    int            `json:"-" db:"attempts"`
+		LastError   *string        `json:"-" db:"last_error"`
+		AvailableAt time.Time      `json:"-" db:"available_at"`
+		CreatedAt   time.Time      `json:"created_at" db:"created_at" example:"0000-01-01T00:00:00.000000Z"`
+		PublishedAt *time.Time     `json:"-" db:"published_at"`
+	}
+
+	// ArticleUpdatedPayload is the updated article along with the status
+	// it had before, which tells an article taken out of the feed from a
+	// draft that was never in it.
+	ArticleUpdatedPayload struct {
+		*Article
+		PreviousStatus ArticleStatus `json:"previous_status,omitempty"`
+	}
+
+	ArticleDeletedPayload struct {
+		ID       uuid.UUID     `json:"id"`
+		AuthorID uuid.UUID     `json:"author_id"`
+		Status   ArticleStatus `json:"status"`
+		Tags     []string      `json:"tags"`
+	}
+
+	UserDeletedPayload struct {
+		ID      uuid.UUID `json:"id"`
+		EraseAt time.Time `json:"erase_at"`
+	}
+)
+
+// NewEvent returns an event about the aggregate with payload encoded
+// as JSON.
+func NewEvent(
+	eventType string,
+	aggregateID uuid.UUID,
+	payload interface{},
+) (*Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Event{
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Payload:     data,
+	}, nil
+}
+
+// OwnerID returns the user the event is about: the author for article
+// events and the user itself for user events.
+func (e *Event) OwnerID() uuid.UUID {
+	if strings.HasPrefix(e.Type, "user.") {
+		return e.AggregateID
+	}
+
+	var payload struct {
+		AuthorID uuid.UUID `json:"author_id"`
+	}
+	if err := json.Unmarshal(e.Payload, &payload); err != nil {
+		return uuid.Nil
+	}
+
+	return payload.AuthorID
+}
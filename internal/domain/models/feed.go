@@ -0,0 +1,142 @@
+package models
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// FeedEvent is a change to the public article feed. Only published
+	// articles are part of it, so an article that is unpublished is sent
+	// as deleted.
+	FeedEvent struct {
+		ID        string    `json:"id,omitempty" example:"1526919030474-0"`
+		Type      string    `json:"type" example:"article.created"`
+		ArticleID uuid.UUID `json:"article_id" example:"00000000-0000-0000-0000-000000000000"`
+		AuthorID  uuid.UUID `json:"author_id" example:"00000000-0000-0000-0000-000000000000"`
+		Tags      []string  `json:"tags" example:"golang"`
+		Article   *Article  `json:"article,omitempty"`
+	}
+
+	// FeedFilter selects the feed events of any of the authors or tags.
+	// An empty filter selects every event.
+	FeedFilter struct {
+		AuthorIDs []uuid.UUID
+		Tags      []string
+	}
+)
+
+// NewFeedEvent returns the feed event for a domain event, or nil when the
+// event does not change the feed.
+func NewFeedEvent(e *Event) (*FeedEvent, error) {
+	switch e.Type {
+	case EventArticleCreated, EventArticleUpdated:
+		payload := &ArticleUpdatedPayload{Article: new(Article)}
+		if err := json.Unmarshal(e.Payload, payload); err != nil {
+			return nil, err
+		}
+		article := payload.Article
+
+		res := &FeedEvent{
+			Type:      e.Type,
+			ArticleID: article.ID,
+			AuthorID:  article.AuthorID,
+			Tags:      article.Tags,
+		}
+
+		if article.Status == ArticlePublished {
+			res.Article = article
+			return res, nil
+		}
+
+		// Only an article that was in the feed is taken out of it, drafts
+		// that were never published must not show up at all.
+		if e.Type == EventArticleUpdated &&
+			payload.PreviousStatus == ArticlePublished {
+			res.Type = EventArticleDeleted
+			return res, nil
+		}
+	case EventArticleDeleted:
+		payload := new(ArticleDeletedPayload)
+		if err := json.Unmarshal(e.Payload, payload); err != nil {
+			return nil, err
+		}
+
+		if payload.Status == ArticlePublished {
+			return &FeedEvent{
+				Type:      e.Type,
+				ArticleID: payload.ID,
+				AuthorID:  payload.AuthorID,
+				Tags:      payload.Tags,
+			}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (e *FeedEvent) Matches(filter *FeedFilter) bool {
+	if len(filter.AuthorIDs) == 0 && len(filter.Tags) == 0 {
+		return true
+	}
+
+	for _, id := range filter.AuthorIDs {
+		if e.AuthorID == id {
+			return true
+		}
+	}
+
+	for _, tag := range filter.Tags {
+		for _, t := range e.Tags {
+			if t == tag {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// After reports whether the event comes after the one with the given ID.
+// IDs are Redis Stream IDs, "<milliseconds>-<sequence>".
+func (e *FeedEvent) After(id string) bool {
+	ms, seq := parseStreamID(e.ID)
+	otherMs, otherSeq := parseStreamID(id)
+
+	if ms != otherMs {
+		return ms > otherMs
+	}
+
+	return seq > otherSeq
+}
+
+// ValidFeedEventID reports whether id looks like a Redis Stream ID.
+func ValidFeedEventID(id string) bool {
+	parts := strings.Split(id, "-")
+	if len(parts) != 2 {
+		return false
+	}
+
+	for _, part := range parts {
+		if _, err := strconv.ParseUint(part, 10, 64); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseStreamID(id string) (uint64, uint64) {
+	parts := strings.SplitN(id, "-", 2)
+	ms, _ := strconv.ParseUint(parts[0], 10, 64)
+
+	var seq uint64
+	if len(parts) == 2 {
+		seq, _ = strconv.ParseUint(parts[1], 10, 64)
+	}
+
+	return ms, seq
+}
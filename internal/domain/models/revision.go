@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/slavtov/clean-architecture/pkg/diff"
+)
+
+type (
+	ArticleRevision struct {
+		ArticleID uuid.UUID `json:"article_id" db:"article_id" example:"00000000-0000-0000-0000-000000000000"`
+		Rev       int       `json:"rev" db:"rev" example:"1"`
+		EditorID  uuid.UUID `json:"editor_id" db:"editor_id" example:"00000000-0000-0000-0000-000000000000"`
+		Title     string    `json:"title" db:"title" example:"Title"`
+		Desc      string    `json:"desc" db:"desc" example:"Description"`
+		CreatedAt time.Time `json:"created_at" db:"created_at" example:"0000-01-01T00:00:00.000000Z"`
+	}
+
+	ArticleRevisionsList struct {
+		TotalCount int               `json:"total_count"`
+		Revisions  []ArticleRevision `json:"revisions"`
+	}
+
+	ArticleRevisionDiff struct {
+		ArticleID uuid.UUID `json:"article_id" example:"00000000-0000-0000-0000-000000000000"`
+		From      int       `json:"from" example:"1"`
+		To        int       `json:"to" example:"2"`
+		Title     []diff.Op `json:"title"`
+		Desc      []diff.Op `json:"desc"`
+	}
+)
+
+func NewArticleRevisionDiff(from, to *ArticleRevision) *ArticleRevisionDiff {
+	return &ArticleRevisionDiff{
+		ArticleID: from.ArticleID,
+		From:      from.Rev,
+		To:        to.Rev,
+		Title:     diff.Words(from.Title, to.Title),
+		Desc:      diff.Lines(from.Desc, to.Desc),
+	}
+}
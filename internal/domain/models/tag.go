@@ -0,0 +1,48 @@
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+type (
+	Tag struct {
+		Name  string `json:"name" db:"name" example:"golang"`
+		Count int    `json:"count" db:"count" example:"1"`
+	}
+
+	TagsList struct {
+		TotalCount int   `json:"total_count"`
+		Tags       []Tag `json:"tags"`
+	}
+)
+
+// NormalizeTags lowercases, trims and deduplicates tag names and returns
+// them sorted. A nil slice stays nil so that callers can tell "no tags
+// given" apart from "remove all tags".
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	seen := make(map[string]struct{}, len(tags))
+	res := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+
+		seen[tag] = struct{}{}
+		res = append(res, tag)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
@@ -2,44 +2,197 @@ package models
 
 import (
 	"errors"
+	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/slavtov/clean-architecture/pkg/password"
 )
 
 type (
 	User struct {
-		ID        uuid.UUID `json:"id" db:"id" example:"00000000-0000-0000-0000-000000000000"`
-		Email     string    `json:"email" db:"email" validate:"required,email" example:"test@test.test"`
-		Password  string    `json:"password,omitempty" db:"password" validate:"omitempty,min=6,max=250" swaggerignore:"true"`
-		UpdatedAt time.Time `json:"updated_at" db:"updated_at" example:"0000-01-01T00:00:00.000000Z"`
+		ID              uuid.UUID `json:"id" db:"id" example:"00000000-0000-0000-0000-000000000000"`
+		Email           string    `json:"email" db:"email" validate:"required,email" example:"test@test.test"`
+		Password        string    `json:"password,omitempty" db:"password" validate:"omitempty,max=250" swaggerignore:"true"`
+		CurrentPassword string    `json:"current_password,omitempty" db:"-" swaggerignore:"true"`
+		Role            string    `json:"role" db:"role" example:"user"`
+		Handle          string    `json:"handle" db:"handle" validate:"omitempty,min=3,max=30" example:"test"`
+		// The profile fields are pointers so that an update can tell the
+		// ones left out (nil) from those being cleared ("").
+		DisplayName *string    `json:"display_name" db:"display_name" validate:"omitempty,max=50" example:"Test"`
+		Bio         *string    `json:"bio" db:"bio" validate:"omitempty,max=500" example:"Bio"`
+		AvatarURL   *string    `json:"avatar_url" db:"avatar_url" validate:"omitempty,max=500" example:"https://example.com/avatar.png"`
+		UpdatedAt   time.Time  `json:"updated_at" db:"updated_at" example:"0000-01-01T00:00:00.000000Z"`
+		CreatedAt   time.Time  `json:"created_at" db:"created_at" example:"0000-01-01T00:00:00.000000Z"`
+		DeletedAt   *time.Time `json:"-" db:"deleted_at"`
+		EraseAt     *time.Time `json:"-" db:"erase_at"`
+	}
+
+	// AccountDeletion tells when a deleted account is going to be erased
+	// unless the user logs in again.
+	AccountDeletion struct {
+		EraseAt time.Time `json:"erase_at" example:"0000-01-01T00:00:00.000000Z"`
+	}
+
+	Session struct {
+		ID        uuid.UUID `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+		ExpiresAt time.Time `json:"expires_at" example:"0000-01-01T00:00:00.000000Z"`
+	}
+
+	AuditEntry struct {
+		ID        int64     `json:"id" db:"id" example:"1"`
+		UserID    uuid.UUID `json:"user_id" db:"user_id" example:"00000000-0000-0000-0000-000000000000"`
+		Action    string    `json:"action" db:"action" example:"login"`
+		IP        string    `json:"ip" db:"ip" example:"127.0.0.1"`
+		UserAgent string    `json:"user_agent" db:"user_agent" example:"Mozilla/5.0"`
 		CreatedAt time.Time `json:"created_at" db:"created_at" example:"0000-01-01T00:00:00.000000Z"`
 	}
 
+	// UserExport is everything the service keeps about a user.
+	UserExport struct {
+		ExportedAt time.Time    `json:"exported_at" example:"0000-01-01T00:00:00.000000Z"`
+		Profile    *User        `json:"profile"`
+		Articles   []Article    `json:"articles"`
+		Sessions   []Session    `json:"sessions"`
+		Audit      []AuditEntry `json:"audit"`
+	}
+
 	UsersList struct {
 		TotalCount int    `json:"total_count"`
 		Users      []User `json:"users"`
 	}
 
+	// EmailChange is a pending change of address waiting for the user to
+	// confirm the new one.
+	EmailChange struct {
+		UserID uuid.UUID `json:"user_id"`
+		Email  string    `json:"email"`
+	}
+
+	// Profile is the public representation of a user.
+	Profile struct {
+		ID          uuid.UUID `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+		Handle      string    `json:"handle" example:"test"`
+		DisplayName string    `json:"display_name" example:"Test"`
+		Bio         string    `json:"bio" example:"Bio"`
+		AvatarURL   string    `json:"avatar_url" example:"https://example.com/avatar.png"`
+		CreatedAt   time.Time `json:"created_at" example:"0000-01-01T00:00:00.000000Z"`
+	}
+
+	// UserSummary is the part of a profile shown next to the user's
+	// content.
+	UserSummary struct {
+		ID          uuid.UUID `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+		Handle      string    `json:"handle" example:"test"`
+		DisplayName string    `json:"display_name" example:"Test"`
+		AvatarURL   string    `json:"avatar_url" example:"https://example.com/avatar.png"`
+	}
+
+	ProfilesList struct {
+		TotalCount int       `json:"total_count"`
+		Profiles   []Profile `json:"profiles"`
+	}
+
 	AuthUser struct {
 		User         *User  `json:"user"`
 		TokenType    string `json:"token_type" validate:"required" example:"Bearer"`
 		ExpiresIn    int    `json:"expires_in" validate:"required" example:"300"`
 		AccessToken  string `json:"access_token" validate:"required"`
 		RefreshToken string `json:"refresh_token" validate:"required"`
+		// Restored is set when logging in cancelled a pending deletion.
+		Restored bool `json:"restored,omitempty" example:"false"`
 	}
 )
 
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+const (
+	AuditRegister          = "register"
+	AuditLogin             = "login"
+	AuditLogout            = "logout"
+	AuditLogoutAll         = "logout_all"
+	AuditUpdate            = "update"
+	AuditPasswordChange    = "password_change"
+	AuditEmailChange       = "email_change"
+	AuditDeletionRequest   = "deletion_request"
+	AuditDeletionCancelled = "deletion_cancelled"
+	AuditExport            = "export"
+)
+
+var handlePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
 func (u *User) Validate() error {
 	validate := validator.New()
 
 	u.Email = strings.ToLower(strings.TrimSpace(u.Email))
 	u.Password = strings.TrimSpace(u.Password)
+	u.Handle = strings.ToLower(strings.TrimSpace(u.Handle))
+	trimSpace(u.DisplayName)
+	trimSpace(u.Bio)
+	trimSpace(u.AvatarURL)
+
+	if err := validate.Struct(u); err != nil {
+		return err
+	}
 
-	return validate.Struct(u)
+	if u.Handle != "" && !handlePattern.MatchString(u.Handle) {
+		return errors.New("handle may only contain letters, digits and underscores")
+	}
+
+	if avatarURL := deref(u.AvatarURL); avatarURL != "" {
+		if res, err := url.ParseRequestURI(avatarURL); err != nil ||
+			(res.Scheme != "https" && res.Scheme != "http") || res.Host == "" {
+			return errors.New("avatar_url must be an http or https URL")
+		}
+	}
+
+	return nil
+}
+
+func trimSpace(s *string) {
+	if s != nil {
+		*s = strings.TrimSpace(*s)
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+// GenerateHandle returns a random handle for users who did not pick one.
+func GenerateHandle() string {
+	return "user_" + strings.ReplaceAll(uuid.NewString(), "-", "")[:12]
+}
+
+func (u *User) Profile() Profile {
+	return Profile{
+		ID:          u.ID,
+		Handle:      u.Handle,
+		DisplayName: deref(u.DisplayName),
+		Bio:         deref(u.Bio),
+		AvatarURL:   deref(u.AvatarURL),
+		CreatedAt:   u.CreatedAt,
+	}
+}
+
+func (u *User) Summary() UserSummary {
+	return UserSummary{
+		ID:          u.ID,
+		Handle:      u.Handle,
+		DisplayName: deref(u.DisplayName),
+		AvatarURL:   deref(u.AvatarURL),
+	}
 }
 
 func (u *User) ValidatePassword() error {
@@ -50,31 +203,26 @@ func (u *User) ValidatePassword() error {
 	return nil
 }
 
-func (u *User) HashPassword() error {
-	hashedPassword, err := bcrypt.GenerateFromPassword(
-		[]byte(u.Password),
-		bcrypt.DefaultCost,
-	)
+func (u *User) HashPassword(h *password.Hasher) error {
+	hashedPassword, err := h.Hash(u.Password)
 	if err != nil {
 		return err
 	}
 
-	u.Password = string(hashedPassword)
+	u.Password = hashedPassword
 
 	return nil
 }
 
-func (u *User) ComparePassword(password string) error {
-	if err := bcrypt.CompareHashAndPassword(
-		[]byte(u.Password),
-		[]byte(password),
-	); err != nil {
-		return err
-	}
+func (u *User) ComparePassword(h *password.Hasher, password string) error {
+	return h.Compare(u.Password, password)
+}
 
-	return nil
+func (u *User) IsModerator() bool {
+	return u.Role == RoleModerator || u.Role == RoleAdmin
 }
 
 func (u *User) SanitizePassword() {
 	u.Password = ""
+	u.CurrentPassword = ""
 }
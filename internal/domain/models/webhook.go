@@ -0,0 +1,83 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+	"github.com/lib/pq"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+type (
+	Webhook struct {
+		ID        uuid.UUID      `json:"id" db:"id" example:"00000000-0000-0000-0000-000000000000"`
+		UserID    uuid.UUID      `json:"user_id" db:"user_id" example:"00000000-0000-0000-0000-000000000000"`
+		URL       string         `json:"url" db:"url" validate:"required,url,max=500" example:"https://example.com/webhook"`
+		Events    pq.StringArray `json:"events" db:"events" validate:"required,min=1,max=10,dive,oneof=article.created article.updated article.deleted user.registered user.deleted" swaggertype:"array,string" example:"article.created"`
+		Secret    string         `json:"secret,omitempty" db:"secret" example:"6f1ed002ab5595859014ebf0951522d9"`
+		Active    bool           `json:"active" db:"active" example:"true"`
+		UpdatedAt time.Time      `json:"updated_at" db:"updated_at" example:"0000-01-01T00:00:00.000000Z"`
+		CreatedAt time.Time      `json:"created_at" db:"created_at" example:"0000-01-01T00:00:00.000000Z"`
+	}
+
+	WebhooksList struct {
+		TotalCount int       `json:"total_count"`
+		Webhooks   []Webhook `json:"webhooks"`
+	}
+
+	WebhookDelivery struct {
+		ID             uuid.UUID      `json:"id" db:"id" example:"00000000-0000-0000-0000-000000000000"`
+		WebhookID      uuid.UUID      `json:"webhook_id" db:"webhook_id" example:"00000000-0000-0000-0000-000000000000"`
+		EventID        int64          `json:"event_id" db:"event_id" example:"1"`
+		EventType      string         `json:"event_type" db:"event_type" example:"article.created"`
+		Payload        types.JSONText `json:"payload" db:"payload" swaggertype:"object"`
+		Status         string         `json:"status" db:"status" example:"succeeded"`
+		Attempts       int            `json:"attempts" db:"attempts" example:"1"`
+		NextAttemptAt  time.Time      `json:"next_attempt_at" db:"next_attempt_at" example:"0000-01-01T00:00:00.000000Z"`
+		ResponseStatus *int           `json:"response_status" db:"response_status" example:"200"`
+		ResponseBody   *string        `json:"response_body" db:"response_body" example:"ok"`
+		Error          *string        `json:"error" db:"error"`
+		DurationMs     *int           `json:"duration_ms" db:"duration_ms" example:"42"`
+		CreatedAt      time.Time      `json:"created_at" db:"created_at" example:"0000-01-01T00:00:00.000000Z"`
+		DeliveredAt    *time.Time     `json:"delivered_at" db:"delivered_at" example:"0000-01-01T00:00:00.000000Z"`
+		URL            string         `json:"-" db:"url"`
+		Secret         string         `json:"-" db:"secret"`
+	}
+
+	WebhookDeliveriesList struct {
+		TotalCount int               `json:"total_count"`
+		Deliveries []WebhookDelivery `json:"deliveries"`
+	}
+)
+
+func (w *Webhook) Validate() error {
+	validate := validator.New()
+
+	w.URL = strings.TrimSpace(w.URL)
+
+	if err := validate.Struct(w); err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(w.URL, "https://") &&
+		!strings.HasPrefix(w.URL, "http://") {
+		return errors.New("url must be an http or https URL")
+	}
+
+	return nil
+}
+
+// SanitizeSecret hides the signing secret, which is only shown once when
+// the webhook is created.
+func (w *Webhook) SanitizeSecret() {
+	w.Secret = ""
+}
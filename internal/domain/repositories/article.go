@@ -9,16 +9,51 @@ import (
 
 type (
 	PGArticleRepository interface {
-		GetAll() ([]models.Article, error)
+		GetAll(filter *models.ArticleFilter) ([]models.Article, error)
 		GetByID(id uuid.UUID) (models.Article, error)
+		GetIDBySlug(slug string) (uuid.UUID, error)
+		// GetByIDForUpdate is GetByID that also locks the article until
+		// the end of the transaction.
+		GetByIDForUpdate(id uuid.UUID) (models.Article, error)
+		// GetForUpdate returns the existing articles among ids and locks
+		// them until the end of the transaction.
+		GetForUpdate(ids []uuid.UUID) ([]models.Article, error)
 		Store(a *models.Article) (*models.Article, error)
+		// StoreMany inserts the articles and returns them in the same order.
+		StoreMany(articles []*models.Article) ([]models.Article, error)
 		Update(a *models.Article) (*models.Article, error)
 		Delete(a models.Article) error
+		// DeleteMany deletes the articles of the author among ids and
+		// returns the IDs of the deleted ones.
+		DeleteMany(ids []uuid.UUID, authorID uuid.UUID) ([]uuid.UUID, error)
+		Restore(a models.Article) (*models.Article, error)
+		Purge(before time.Time) (int64, error)
+		PublishDue(limit int) ([]models.Article, error)
+		GetRevisions(id uuid.UUID) ([]models.ArticleRevision, error)
+		GetRevision(id uuid.UUID, rev int) (models.ArticleRevision, error)
+		GetTags() ([]models.Tag, error)
+		Like(id uuid.UUID, userID uuid.UUID) (bool, error)
+		Unlike(id uuid.UUID, userID uuid.UUID) (bool, error)
+		Bookmark(id uuid.UUID, userID uuid.UUID) error
+		Unbookmark(id uuid.UUID, userID uuid.UUID) error
+		GetBookmarks(userID uuid.UUID) ([]models.Article, error)
+		GetReactions(userID uuid.UUID, ids []uuid.UUID) ([]models.ArticleReactions, error)
+		ReconcileLikes(ids []uuid.UUID) ([]models.Article, error)
 	}
 
 	RedisArticleRepository interface {
 		GetByID(id uuid.UUID) (models.Article, error)
+		GetIDBySlug(slug string) (uuid.UUID, error)
 		SetArticle(article *models.Article, exp time.Duration) error
+		SetArticles(articles []*models.Article, exp time.Duration) error
+		SetSlug(slug string, id uuid.UUID, exp time.Duration) error
+		GetDescHTML(articles []*models.Article) ([]string, error)
+		SetDescHTML(article *models.Article, exp time.Duration) error
 		Delete(id uuid.UUID) error
+		DeleteMany(ids []uuid.UUID) error
+		IncrLikes(id uuid.UUID, incr int64) error
+		GetLikes(ids []uuid.UUID) ([]int64, error)
+		TakeLikes() ([]uuid.UUID, error)
+		ClearLikes() error
 	}
 )
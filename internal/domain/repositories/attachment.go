@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+)
+
+type PGAttachmentRepository interface {
+	GetAll(articleID uuid.UUID) ([]models.Attachment, error)
+	GetByID(id uuid.UUID) (models.Attachment, error)
+	Store(a *models.Attachment) (*models.Attachment, error)
+	Delete(id uuid.UUID) error
+	Purge(before time.Time) ([]string, error)
+}
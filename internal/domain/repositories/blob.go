@@ -0,0 +1,11 @@
+package repositories
+
+import "io"
+
+// BlobStore keeps the contents of uploaded files by key. Metadata lives
+// in Postgres, so implementations only need to store bytes.
+type BlobStore interface {
+	Put(key string, r io.Reader, size int64, contentType string) error
+	Get(key string) (io.ReadSeekCloser, error)
+	Delete(key string) error
+}
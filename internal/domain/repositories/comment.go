@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+)
+
+type (
+	PGCommentRepository interface {
+		GetAll(filter *models.CommentFilter) ([]models.Comment, int, error)
+		GetByID(id uuid.UUID) (models.Comment, error)
+		Store(c *models.Comment) (*models.Comment, error)
+		Update(c *models.Comment) (*models.Comment, error)
+		Delete(c models.Comment) error
+		Hide(id uuid.UUID, moderatorID uuid.UUID) (*models.Comment, error)
+		Unhide(id uuid.UUID) (*models.Comment, error)
+		Purge(before time.Time) (int64, error)
+	}
+
+	RedisCommentRepository interface {
+		GetAll(filter *models.CommentFilter) (models.CommentsList, error)
+		SetComments(filter *models.CommentFilter, list *models.CommentsList, exp time.Duration) error
+		DeleteAll(articleID uuid.UUID) error
+	}
+)
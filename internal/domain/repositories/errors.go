@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrArticleConflict = echo.NewHTTPError(
+		http.StatusPreconditionFailed,
+		"article has been modified",
+	)
+	ErrUserConflict = echo.NewHTTPError(
+		http.StatusPreconditionFailed,
+		"user has been modified",
+	)
+	ErrFileTooLarge = echo.NewHTTPError(
+		http.StatusRequestEntityTooLarge,
+		"file is too large",
+	)
+)
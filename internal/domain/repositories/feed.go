@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+)
+
+type RedisFeedRepository interface {
+	Append(e *models.FeedEvent) error
+	LastID() (string, error)
+	Since(id string, limit int64) ([]models.FeedEvent, error)
+	// Subscribe returns the IDs of the events as they are appended, not
+	// necessarily in order.
+	Subscribe(ctx context.Context) (<-chan string, error)
+}
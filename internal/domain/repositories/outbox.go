@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+)
+
+type PGOutboxRepository interface {
+	Store(e *models.Event) (*models.Event, error)
+	// Lease claims up to limit pending events that are due for the given
+	// duration.
+	Lease(limit int, lease time.Duration) ([]models.Event, error)
+	GetDead() ([]models.Event, error)
+	MarkPublished(id int64) error
+	MarkFailed(id int64, status string, reason string, retryAt time.Time) error
+	Requeue(id int64) error
+	Purge(before time.Time) (int64, error)
+}
+
+// EventSink publishes outbox events to the outside world. Delivery is at
+// least once, so consumers have to tolerate an event arriving twice.
+type EventSink interface {
+	Publish(e *models.Event) error
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import "database/sql"
+
+// TxRepositories are repositories bound to a single transaction.
+type TxRepositories struct {
+	Users       PGUserRepository
+	Articles    PGArticleRepository
+	Comments    PGCommentRepository
+	Attachments PGAttachmentRepository
+	Outbox      PGOutboxRepository
+	Webhooks    PGWebhookRepository
+}
+
+// UnitOfWork runs fn with repositories that share one transaction. It is
+// committed when fn returns nil and rolled back when fn returns an error
+// or panics. Nil opts use the default isolation level of the database.
+type UnitOfWork interface {
+	Do(opts *sql.TxOptions, fn func(r *TxRepositories) error) error
+}
@@ -11,18 +11,36 @@ type (
 	PGUserRepository interface {
 		GetAll() ([]models.User, error)
 		GetByID(id uuid.UUID) (models.User, error)
+		// GetByIDs returns the existing users among ids, in no particular
+		// order.
+		GetByIDs(ids []uuid.UUID) ([]models.User, error)
+		GetByHandle(handle string) (models.User, error)
 		FindByEmail(email string) (models.User, error)
+		FindDeletedByEmail(email string) (models.User, error)
 		Store(u *models.User) (*models.User, error)
 		Update(u *models.User) (*models.User, error)
-		Delete(id uuid.UUID) error
+		SetPassword(id uuid.UUID, hash string) error
+		SetRole(id uuid.UUID, role string) (*models.User, error)
+		Delete(id uuid.UUID, eraseAt time.Time) ([]uuid.UUID, error)
+		Restore(id uuid.UUID) (*models.User, error)
+		Purge(before time.Time, grace time.Duration) (int64, []string, error)
+		GetAudit(userID uuid.UUID) ([]models.AuditEntry, error)
+		StoreAudit(entry *models.AuditEntry) error
 	}
 
 	RedisUserRepository interface {
 		GetByID(id uuid.UUID) (models.User, error)
+		// GetByIDs returns the cached users among ids.
+		GetByIDs(ids []uuid.UUID) ([]models.User, error)
 		GetTokenInfo(id uuid.UUID, tokenID uuid.UUID) (uuid.UUID, error)
+		GetSessions(id uuid.UUID) ([]models.Session, error)
 		SetToken(id uuid.UUID, tokenID uuid.UUID, exp int64) error
 		SetUser(user *models.User, exp time.Duration) error
+		SetEmailChange(token string, change *models.EmailChange, exp time.Duration) error
+		TakeEmailChange(token string) (models.EmailChange, error)
+		DeleteEmailChange(token string) error
 		Delete(keys ...string) error
 		DeleteAll(pattern string) error
+		DeleteAllExcept(pattern string, keep ...string) error
 	}
 )
@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+)
+
+type PGWebhookRepository interface {
+	GetAll(userID uuid.UUID) ([]models.Webhook, error)
+	GetByID(id uuid.UUID) (models.Webhook, error)
+	Count(userID uuid.UUID) (int, error)
+	Store(w *models.Webhook) (*models.Webhook, error)
+	Update(w *models.Webhook) (*models.Webhook, error)
+	Delete(id uuid.UUID, userID uuid.UUID) error
+	Enqueue(e *models.Event, ownerID uuid.UUID, body []byte) (int64, error)
+	GetDeliveries(webhookID uuid.UUID, limit int) ([]models.WebhookDelivery, error)
+	GetDelivery(id uuid.UUID, webhookID uuid.UUID) (models.WebhookDelivery, error)
+	// LeaseDeliveries claims up to limit pending deliveries that are due
+	// for the given duration.
+	LeaseDeliveries(limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+	// ClaimDelivery leases a delivery that is not pending for a
+	// redelivery.
+	ClaimDelivery(id uuid.UUID, webhookID uuid.UUID, lease time.Duration) (models.WebhookDelivery, error)
+	UpdateDelivery(d *models.WebhookDelivery) (*models.WebhookDelivery, error)
+	PurgeDeliveries(before time.Time) (int64, error)
+}
@@ -1,14 +1,35 @@
 package usecases
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/slavtov/clean-architecture/internal/domain/models"
 )
 
 type ArticleUseCase interface {
-	GetAll() ([]models.Article, error)
-	GetByID(id uuid.UUID) (models.Article, error)
+	GetAll(filter *models.ArticleFilter) ([]models.Article, error)
+	GetByID(id uuid.UUID, viewerID uuid.UUID) (models.Article, error)
+	GetBySlug(slug string, viewerID uuid.UUID) (models.Article, error)
 	Store(a *models.Article) (*models.Article, error)
+	StoreMany(articles []*models.Article) ([]models.ArticleBulkResult, error)
 	Update(a *models.Article) (*models.Article, error)
+	UpdateMany(articles []*models.Article) ([]models.ArticleBulkResult, error)
 	Delete(a models.Article) error
+	DeleteMany(ids []uuid.UUID, authorID uuid.UUID) ([]models.ArticleBulkResult, error)
+	Restore(a models.Article) (*models.Article, error)
+	Purge(before time.Time) (int64, error)
+	PublishDue(limit int) (int, error)
+	GetRevisions(id uuid.UUID, viewerID uuid.UUID) ([]models.ArticleRevision, error)
+	GetRevision(id uuid.UUID, rev int, viewerID uuid.UUID) (models.ArticleRevision, error)
+	DiffRevisions(id uuid.UUID, from, to int, viewerID uuid.UUID) (*models.ArticleRevisionDiff, error)
+	RestoreRevision(a models.Article, rev int) (*models.Article, error)
+	GetTags() ([]models.Tag, error)
+	Like(id uuid.UUID, userID uuid.UUID) error
+	Unlike(id uuid.UUID, userID uuid.UUID) error
+	Bookmark(id uuid.UUID, userID uuid.UUID) error
+	Unbookmark(id uuid.UUID, userID uuid.UUID) error
+	GetBookmarks(userID uuid.UUID) ([]models.Article, error)
+	ReconcileLikes() (int, error)
+	Render(articles ...*models.Article)
 }
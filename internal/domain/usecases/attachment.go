@@ -0,0 +1,17 @@
+package usecases
+
+import (
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+)
+
+type AttachmentUseCase interface {
+	GetAll(articleID uuid.UUID, viewerID uuid.UUID) ([]models.Attachment, error)
+	Open(id uuid.UUID, viewerID uuid.UUID) (models.Attachment, io.ReadSeekCloser, error)
+	Store(attachment *models.Attachment, file io.ReadSeeker) (*models.Attachment, error)
+	Delete(attachment models.Attachment) error
+	Purge(before time.Time) (int64, error)
+}
@@ -0,0 +1,18 @@
+package usecases
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+)
+
+type CommentUseCase interface {
+	GetAll(filter *models.CommentFilter, viewerID uuid.UUID) (models.CommentsList, error)
+	Store(comment *models.Comment) (*models.Comment, error)
+	Update(comment *models.Comment) (*models.Comment, error)
+	Delete(comment models.Comment) error
+	Hide(id uuid.UUID, moderatorID uuid.UUID) (*models.Comment, error)
+	Unhide(id uuid.UUID, moderatorID uuid.UUID) (*models.Comment, error)
+	Purge(before time.Time) (int64, error)
+}
@@ -0,0 +1,13 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+)
+
+type FeedUseCase interface {
+	Publish(e *models.Event) error
+	Replay(lastID string, filter *models.FeedFilter) ([]models.FeedEvent, error)
+	Subscribe(ctx context.Context, filter *models.FeedFilter) (<-chan models.FeedEvent, error)
+}
@@ -0,0 +1,14 @@
+package usecases
+
+import (
+	"time"
+
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+)
+
+type OutboxUseCase interface {
+	Relay(limit int) (int, error)
+	GetDead() ([]models.Event, error)
+	Requeue(id int64) error
+	Purge(before time.Time) (int64, error)
+}
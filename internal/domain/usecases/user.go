@@ -1,6 +1,8 @@
 package usecases
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/slavtov/clean-architecture/internal/domain/models"
 	"github.com/slavtov/clean-architecture/pkg/utils"
@@ -13,15 +15,25 @@ type (
 		DeleteToken(id uuid.UUID, tokenID uuid.UUID) error
 		Logout(id uuid.UUID, tokenID *utils.TokenDetails) error
 		LogoutAll(id uuid.UUID) error
+		LogoutOthers(id uuid.UUID, td *utils.TokenDetails) error
 	}
 
 	UserUseCase interface {
 		GetAll() ([]models.User, error)
 		GetByID(id uuid.UUID) (models.User, error)
+		GetByIDs(ids []uuid.UUID) ([]models.User, error)
+		GetByHandle(handle string) (models.User, error)
 		Login(user *models.User) (*models.AuthUser, error)
 		Store(user *models.User) (*models.AuthUser, error)
-		Update(user *models.User) (*models.User, error)
-		Delete(id uuid.UUID) error
+		Create(user *models.User) (*models.User, error)
+		Update(user *models.User, session *utils.TokenDetails) (*models.User, error)
+		ConfirmEmail(token string) (*models.User, error)
+		SetRole(id uuid.UUID, role string) (*models.User, error)
+		Delete(id uuid.UUID, audit *models.AuditEntry) (*models.AccountDeletion, error)
+		Restore(user *models.User) (*models.AuthUser, error)
+		Purge(before time.Time) (int64, error)
+		Export(id uuid.UUID) (*models.UserExport, error)
+		Audit(entry *models.AuditEntry) error
 		jwtUseCase
 	}
 )
@@ -0,0 +1,21 @@
+package usecases
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+)
+
+type WebhookUseCase interface {
+	GetAll(userID uuid.UUID) ([]models.Webhook, error)
+	GetByID(id uuid.UUID, userID uuid.UUID) (models.Webhook, error)
+	Store(w *models.Webhook) (*models.Webhook, error)
+	Update(w *models.Webhook) (*models.Webhook, error)
+	Delete(id uuid.UUID, userID uuid.UUID) error
+	GetDeliveries(webhookID uuid.UUID, userID uuid.UUID) ([]models.WebhookDelivery, error)
+	Redeliver(id uuid.UUID, webhookID uuid.UUID, userID uuid.UUID) (*models.WebhookDelivery, error)
+	Publish(e *models.Event) error
+	Deliver(limit int) (int, error)
+	Purge(before time.Time) (int64, error)
+}
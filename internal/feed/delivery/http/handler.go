@@ -0,0 +1,336 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/config"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/usecases"
+	"github.com/slavtov/clean-architecture/internal/middleware"
+	"github.com/slavtov/clean-architecture/pkg/logger"
+)
+
+type handler struct {
+	cfg         *config.Config
+	shutdown    context.Context
+	feedUseCase usecases.FeedUseCase
+	upgrader    websocket.Upgrader
+	log         logger.Logger
+}
+
+const (
+	headerLastEventID = "Last-Event-ID"
+	writeTimeout      = 10 * time.Second
+	maxMessageSize    = 512
+)
+
+func newHandler(
+	cfg *config.Config,
+	shutdown context.Context,
+	fu usecases.FeedUseCase,
+	log logger.Logger,
+) *handler {
+	h := &handler{
+		cfg:         cfg,
+		shutdown:    shutdown,
+		feedUseCase: fu,
+		log:         log,
+	}
+	h.upgrader.CheckOrigin = h.checkOrigin
+
+	return h
+}
+
+// Init registers the feed routes. The streams end when the shutdown
+// context is done, so that clients reconnect to another replica.
+func Init(
+	cfg *config.Config,
+	shutdown context.Context,
+	e *echo.Group,
+	fu usecases.FeedUseCase,
+	uu usecases.UserUseCase,
+	log logger.Logger,
+) {
+	h := newHandler(cfg, shutdown, fu, log)
+	auth := middleware.Auth(cfg, uu, log)
+
+	e.GET("/feed", h.Stream, auth)
+	e.GET("/feed/ws", h.WebSocket, auth)
+}
+
+// Stream godoc
+// @Tags Feed
+// @Summary Stream article events
+// @Description Server-Sent Events of published articles being created, updated and deleted. Articles that are unpublished are sent as deleted. With author or tag only the events of any of them are sent. After a reconnect the events missed since Last-Event-ID are sent first, as far back as the feed keeps them. Comments are sent as heartbeats.
+// @Produce text/event-stream
+// @Param author query []string false "Author IDs" collectionFormat(multi)
+// @Param tag query []string false "Tags" collectionFormat(multi)
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Param last_event_id query string false "Same as Last-Event-ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.FeedEvent
+// @Failure 400,401,500 {object} swagger.Error
+// @Router /feed [get]
+func (h *handler) Stream(c echo.Context) error {
+	ctx, cancel := h.streamContext(c)
+	defer cancel()
+
+	events, backlog, lastID, err := h.subscribe(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(middleware.HeaderCacheControl, "no-cache")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	return h.follow(ctx, events, backlog, lastID, func(e *models.FeedEvent) error {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(
+			res,
+			"id: %s\nevent: %s\ndata: %s\n\n",
+			e.ID,
+			e.Type,
+			data,
+		); err != nil {
+			return err
+		}
+
+		res.Flush()
+
+		return nil
+	}, func() error {
+		if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+			return err
+		}
+
+		res.Flush()
+
+		return nil
+	})
+}
+
+// WebSocket godoc
+// @Tags Feed
+// @Summary Stream article events over WebSocket
+// @Description The events of GET /feed as JSON text messages, with the same filters. Browsers cannot set Last-Event-ID, so last_event_id resumes instead. Heartbeats are ping frames.
+// @Param author query []string false "Author IDs" collectionFormat(multi)
+// @Param tag query []string false "Tags" collectionFormat(multi)
+// @Param last_event_id query string false "ID of the last event received"
+// @Security ApiKeyAuth
+// @Success 101 {object} models.FeedEvent
+// @Failure 400,401,403,500 {object} swagger.Error
+// @Router /feed/ws [get]
+func (h *handler) WebSocket(c echo.Context) error {
+	ctx, cancel := h.streamContext(c)
+	defer cancel()
+
+	events, backlog, lastID, err := h.subscribe(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		// The upgrader has already replied with an error.
+		h.log.Errorf("feed.Upgrade: %v", err)
+		return nil
+	}
+	defer conn.Close()
+
+	heartbeat := time.Second * time.Duration(h.cfg.Feed.Heartbeat)
+
+	// Clients are not expected to send anything, but reading is what
+	// handles pongs and the close handshake.
+	conn.SetReadLimit(maxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(2 * heartbeat))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * heartbeat))
+	})
+
+	go func() {
+		defer cancel()
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	return h.follow(ctx, events, backlog, lastID, func(e *models.FeedEvent) error {
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		return conn.WriteJSON(e)
+	}, func() error {
+		return conn.WriteControl(
+			websocket.PingMessage,
+			nil,
+			time.Now().Add(writeTimeout),
+		)
+	})
+}
+
+// streamContext returns a context that ends with the request or when the
+// server starts shutting down, whichever comes first.
+func (h *handler) streamContext(
+	c echo.Context,
+) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(c.Request().Context())
+
+	go func() {
+		select {
+		case <-h.shutdown.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// subscribe starts following the feed before reading the missed events,
+// so that nothing falls in between. follow drops the duplicates.
+func (h *handler) subscribe(
+	ctx context.Context,
+	c echo.Context,
+) (<-chan models.FeedEvent, []models.FeedEvent, string, error) {
+	filter, err := getFilter(c)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	lastID := c.Request().Header.Get(headerLastEventID)
+	if lastID == "" {
+		lastID = c.QueryParam("last_event_id")
+	}
+
+	events, err := h.feedUseCase.Subscribe(ctx, filter)
+	if err != nil {
+		h.log.Errorf("feed.UseCase.Subscribe: %v", err)
+		return nil, nil, "", err
+	}
+
+	var backlog []models.FeedEvent
+	if lastID != "" {
+		if backlog, err = h.feedUseCase.Replay(lastID, filter); err != nil {
+			h.log.Errorf("feed.UseCase.Replay: %v", err)
+			return nil, nil, "", err
+		}
+	}
+
+	return events, backlog, lastID, nil
+}
+
+// follow sends the missed events and then the live ones, with a ping
+// whenever the feed has been quiet for a heartbeat. It returns when the
+// client goes away, is dropped for falling behind or the server shuts
+// down.
+func (h *handler) follow(
+	ctx context.Context,
+	events <-chan models.FeedEvent,
+	backlog []models.FeedEvent,
+	lastID string,
+	send func(e *models.FeedEvent) error,
+	ping func() error,
+) error {
+	for i := range backlog {
+		if err := send(&backlog[i]); err != nil {
+			return nil
+		}
+
+		lastID = backlog[i].ID
+	}
+
+	heartbeat := time.NewTicker(time.Second * time.Duration(h.cfg.Feed.Heartbeat))
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			if lastID != "" && !e.After(lastID) {
+				continue
+			}
+
+			if err := send(&e); err != nil {
+				return nil
+			}
+
+			lastID = e.ID
+		case <-heartbeat.C:
+			if err := ping(); err != nil {
+				return nil
+			}
+		}
+	}
+}
+
+// checkOrigin accepts WebSocket connections from the API host itself and
+// the configured origins. Browsers send cookies along with cross-origin
+// WebSocket requests, so anything else has to be refused.
+func (h *handler) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get(echo.HeaderOrigin)
+	if origin == "" {
+		return true
+	}
+
+	for _, allowed := range h.cfg.Feed.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+func getFilter(c echo.Context) (*models.FeedFilter, error) {
+	filter := new(models.FeedFilter)
+
+	for _, param := range c.QueryParams()["author"] {
+		for _, s := range strings.Split(param, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(s))
+			if err != nil {
+				return nil, echo.NewHTTPError(
+					http.StatusBadRequest,
+					"invalid author id",
+				)
+			}
+
+			filter.AuthorIDs = append(filter.AuthorIDs, id)
+		}
+	}
+
+	var tags []string
+	for _, param := range c.QueryParams()["tag"] {
+		tags = append(tags, strings.Split(param, ",")...)
+	}
+	filter.Tags = models.NormalizeTags(tags)
+
+	return filter, nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/pkg/store/redis"
+	"github.com/slavtov/clean-architecture/pkg/utils"
+)
+
+type redisRepository struct {
+	redis  redis.Store
+	maxLen int64
+}
+
+const (
+	prefix     = "feed"
+	streamKey  = "stream"
+	channelKey = "events"
+	dataField  = "data"
+)
+
+// NewRedisRepository returns the feed kept in a Redis Stream of about
+// maxLen events, which is how far back subscribers can resume.
+func NewRedisRepository(
+	rdb redis.Store,
+	maxLen int64,
+) repositories.RedisFeedRepository {
+	return &redisRepository{rdb, maxLen}
+}
+
+// Append adds the event to the stream, which assigns its ID, and then
+// announces the ID to every replica. Replicas may receive announcements
+// out of order, so they read the events themselves from the stream.
+func (r *redisRepository) Append(e *models.FeedEvent) error {
+	e.ID = ""
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	if e.ID, err = r.redis.XAdd(
+		utils.GetRedisKey(prefix, streamKey),
+		r.maxLen,
+		map[string]interface{}{dataField: data},
+	); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	if err := r.redis.Publish(
+		utils.GetRedisKey(prefix, channelKey),
+		e.ID,
+	); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// LastID returns the ID of the latest event, or "0-0" while the stream is
+// empty.
+func (r *redisRepository) LastID() (string, error) {
+	entries, err := r.redis.XRevRange(
+		utils.GetRedisKey(prefix, streamKey),
+		"+",
+		"-",
+		1,
+	)
+	if err != nil {
+		return "", echo.ErrInternalServerError
+	}
+
+	if len(entries) == 0 {
+		return "0-0", nil
+	}
+
+	return entries[0].ID, nil
+}
+
+// Since returns up to limit events that follow the one with the given ID.
+func (r *redisRepository) Since(
+	id string,
+	limit int64,
+) ([]models.FeedEvent, error) {
+	// The range is inclusive, so it takes one more and skips id itself.
+	entries, err := r.redis.XRange(
+		utils.GetRedisKey(prefix, streamKey),
+		id,
+		"+",
+		limit+1,
+	)
+	if err != nil {
+		return nil, echo.ErrInternalServerError
+	}
+
+	events := make([]models.FeedEvent, 0, len(entries))
+	for _, entry := range entries {
+		if entry.ID == id {
+			continue
+		}
+
+		data, ok := entry.Values[dataField].(string)
+		if !ok {
+			continue
+		}
+
+		var e models.FeedEvent
+		if err := json.Unmarshal([]byte(data), &e); err != nil {
+			continue
+		}
+
+		e.ID = entry.ID
+		events = append(events, e)
+	}
+
+	if int64(len(events)) > limit {
+		events = events[:limit]
+	}
+
+	return events, nil
+}
+
+// Subscribe returns the IDs of the events appended by any replica until
+// ctx is done.
+func (r *redisRepository) Subscribe(ctx context.Context) (<-chan string, error) {
+	ids, err := r.redis.Subscribe(ctx, utils.GetRedisKey(prefix, channelKey))
+	if err != nil {
+		return nil, echo.ErrInternalServerError
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/config"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/internal/domain/usecases"
+	"github.com/slavtov/clean-architecture/pkg/logger"
+)
+
+type usecase struct {
+	cfg             *config.Config
+	redisRepository repositories.RedisFeedRepository
+	articleUseCase  usecases.ArticleUseCase
+	log             logger.Logger
+
+	mu          sync.Mutex
+	listening   bool
+	subscribers map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	filter *models.FeedFilter
+	events chan models.FeedEvent
+}
+
+func New(
+	cfg *config.Config,
+	redis repositories.RedisFeedRepository,
+	au usecases.ArticleUseCase,
+	log logger.Logger,
+) usecases.FeedUseCase {
+	return &usecase{
+		cfg:             cfg,
+		redisRepository: redis,
+		articleUseCase:  au,
+		log:             log,
+		subscribers:     make(map[*subscriber]struct{}),
+	}
+}
+
+// Publish adds the article events that change the feed to it. It is
+// called by the outbox relay, which makes it an event sink.
+func (u *usecase) Publish(e *models.Event) error {
+	res, err := models.NewFeedEvent(e)
+	if err != nil {
+		u.log.Errorf("feed.NewFeedEvent: %v", err)
+		return err
+	}
+
+	if res == nil {
+		return nil
+	}
+
+	if res.Article != nil {
+		u.articleUseCase.Render(res.Article)
+	}
+
+	if err := u.redisRepository.Append(res); err != nil {
+		u.log.Errorf("feed.redisRepository.Append: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Replay returns the events of the filter that followed the one with the
+// given ID, as far back as the stream goes.
+func (u *usecase) Replay(
+	lastID string,
+	filter *models.FeedFilter,
+) ([]models.FeedEvent, error) {
+	if !models.ValidFeedEventID(lastID) {
+		return nil, echo.NewHTTPError(
+			http.StatusBadRequest,
+			"invalid last event id",
+		)
+	}
+
+	events, err := u.redisRepository.Since(
+		lastID,
+		int64(u.cfg.Feed.ReplayLimit),
+	)
+	if err != nil {
+		u.log.Errorf("feed.redisRepository.Since: %v", err)
+		return nil, err
+	}
+
+	res := events[:0]
+	for _, e := range events {
+		if e.Matches(filter) {
+			res = append(res, e)
+		}
+	}
+
+	return res, nil
+}
+
+// Subscribe returns the live events of the filter until ctx is done. A
+// subscriber that falls behind is dropped and its channel closed; it is
+// expected to reconnect and resume from the last event it received.
+func (u *usecase) Subscribe(
+	ctx context.Context,
+	filter *models.FeedFilter,
+) (<-chan models.FeedEvent, error) {
+	sub := &subscriber{
+		filter: filter,
+		events: make(chan models.FeedEvent, u.cfg.Feed.BufferSize),
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if err := u.listen(); err != nil {
+		return nil, err
+	}
+
+	u.subscribers[sub] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+
+		u.mu.Lock()
+		u.drop(sub)
+		u.mu.Unlock()
+	}()
+
+	return sub.events, nil
+}
+
+// listen starts the one Redis subscription that the subscribers of this
+// replica share. It must be called with mu held.
+func (u *usecase) listen() error {
+	if u.listening {
+		return nil
+	}
+
+	// The position is taken before subscribing, so that every event
+	// announced from then on comes after it.
+	lastID, err := u.redisRepository.LastID()
+	if err != nil {
+		u.log.Errorf("feed.redisRepository.LastID: %v", err)
+		return err
+	}
+
+	ids, err := u.redisRepository.Subscribe(context.Background())
+	if err != nil {
+		u.log.Errorf("feed.redisRepository.Subscribe: %v", err)
+		return err
+	}
+
+	u.listening = true
+
+	go u.broadcast(ids, lastID)
+
+	return nil
+}
+
+// broadcast sends the events to the subscribers in stream order. The
+// announcements only say that something was appended: replicas can
+// announce in a different order than their events were added, so the
+// events are read from the stream, following the last one sent.
+func (u *usecase) broadcast(ids <-chan string, lastID string) {
+	for id := range ids {
+		announced := models.FeedEvent{ID: id}
+		if announced.After(lastID) {
+			lastID = u.catchUp(lastID)
+		}
+	}
+
+	// The subscription only ends with the process, but if it does the
+	// subscribers reconnect and the next one starts a new subscription.
+	u.mu.Lock()
+	for sub := range u.subscribers {
+		u.drop(sub)
+	}
+	u.listening = false
+	u.mu.Unlock()
+}
+
+// catchUp sends the events that follow lastID and returns the ID of the
+// last one sent. Events it fails to read are read again on the next
+// announcement.
+func (u *usecase) catchUp(lastID string) string {
+	limit := int64(u.cfg.Feed.ReplayLimit)
+
+	for {
+		events, err := u.redisRepository.Since(lastID, limit)
+		if err != nil {
+			u.log.Errorf("feed.redisRepository.Since: %v", err)
+			return lastID
+		}
+
+		u.mu.Lock()
+		for _, e := range events {
+			for sub := range u.subscribers {
+				if !e.Matches(sub.filter) {
+					continue
+				}
+
+				select {
+				case sub.events <- e:
+				default:
+					u.drop(sub)
+				}
+			}
+
+			lastID = e.ID
+		}
+		u.mu.Unlock()
+
+		if int64(len(events)) < limit {
+			return lastID
+		}
+	}
+}
+
+// drop removes the subscriber and closes its channel. It must be called
+// with mu held.
+func (u *usecase) drop(sub *subscriber) {
+	if _, ok := u.subscribers[sub]; !ok {
+		return
+	}
+
+	delete(u.subscribers, sub)
+	close(sub.events)
+}
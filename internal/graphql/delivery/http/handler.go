@@ -0,0 +1,128 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/config"
+	"github.com/slavtov/clean-architecture/internal/domain/usecases"
+	"github.com/slavtov/clean-architecture/internal/middleware"
+	"github.com/slavtov/clean-architecture/pkg/logger"
+	"github.com/slavtov/clean-architecture/pkg/utils"
+)
+
+type handler struct {
+	cfg            *config.Config
+	schema         graphql.Schema
+	articleUseCase usecases.ArticleUseCase
+	userUseCase    usecases.UserUseCase
+	log            logger.Logger
+}
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func newHandler(
+	cfg *config.Config,
+	au usecases.ArticleUseCase,
+	uu usecases.UserUseCase,
+	log logger.Logger,
+) *handler {
+	h := &handler{
+		cfg:            cfg,
+		articleUseCase: au,
+		userUseCase:    uu,
+		log:            log,
+	}
+
+	schema, err := h.newSchema()
+	if err != nil {
+		log.Fatalf("graphql.NewSchema: %v", err)
+	}
+	h.schema = schema
+
+	return h
+}
+
+func Init(
+	cfg *config.Config,
+	e *echo.Group,
+	au usecases.ArticleUseCase,
+	uu usecases.UserUseCase,
+	log logger.Logger,
+) {
+	h := newHandler(cfg, au, uu, log)
+	optionalAuth := middleware.OptionalAuth(cfg, uu, log)
+
+	e.POST("/graphql", h.Query, optionalAuth)
+}
+
+// Query godoc
+// @Tags GraphQL
+// @Summary Run a GraphQL query or mutation
+// @Description Articles, users and the viewer, with article mutations for authenticated callers.
+// @Description Queries deeper than graphql.max_depth or costlier than graphql.max_complexity are rejected.
+// @Accept json
+// @Produce json
+// @Param body body swagger.GraphQLRequest true "Body"
+// @Security ApiKeyAuth
+// @Success 200 {object} swagger.GraphQLResponse
+// @Failure 400 {object} swagger.Error
+// @Router /graphql [post]
+func (h *handler) Query(c echo.Context) error {
+	req := new(request)
+
+	if err := c.Bind(req); err != nil || req.Query == "" {
+		return echo.ErrBadRequest
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{
+			Body: []byte(req.Query),
+			Name: "GraphQL request",
+		}),
+	})
+	if err != nil {
+		return c.JSON(http.StatusOK, &graphql.Result{
+			Errors: gqlerrors.FormatErrors(err),
+		})
+	}
+
+	if res := graphql.ValidateDocument(&h.schema, doc, nil); !res.IsValid {
+		return c.JSON(http.StatusOK, &graphql.Result{Errors: res.Errors})
+	}
+
+	if err := checkLimits(
+		&h.schema,
+		doc,
+		h.cfg.GraphQL.MaxDepth,
+		h.cfg.GraphQL.MaxComplexity,
+	); err != nil {
+		return c.JSON(http.StatusOK, &graphql.Result{
+			Errors: gqlerrors.FormatErrors(err),
+		})
+	}
+
+	ctx := context.WithValue(
+		c.Request().Context(),
+		viewerKey,
+		utils.GetCtxViewerID(c),
+	)
+	ctx = context.WithValue(ctx, loaderKey, newUserLoader(h.userUseCase, h.log))
+
+	return c.JSON(http.StatusOK, graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	}))
+}
@@ -0,0 +1,183 @@
+package http
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// listComplexity is the number of items a list field is assumed to
+// return when estimating the cost of a query.
+const listComplexity = 10
+
+// checkLimits rejects documents with an operation nested deeper than
+// maxDepth or costing more than maxComplexity. Every field costs 1 and
+// the fields selected under a list cost listComplexity times as much.
+// Introspection fields are counted like any other, except __typename,
+// which has no selection and is free.
+func checkLimits(
+	schema *graphql.Schema,
+	doc *ast.Document,
+	maxDepth int,
+	maxComplexity int,
+) error {
+	w := &limitsWalker{
+		schema:    schema,
+		fragments: make(map[string]*ast.FragmentDefinition),
+	}
+
+	for _, def := range doc.Definitions {
+		if frag, ok := def.(*ast.FragmentDefinition); ok {
+			w.fragments[frag.Name.Value] = frag
+		}
+	}
+
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		var root *graphql.Object
+		switch op.Operation {
+		case ast.OperationTypeQuery:
+			root = schema.QueryType()
+		case ast.OperationTypeMutation:
+			root = schema.MutationType()
+		default:
+			return fmt.Errorf("%s operations are not supported", op.Operation)
+		}
+
+		depth, complexity := w.walk(op.SelectionSet, root, 0)
+		if depth > maxDepth {
+			return fmt.Errorf(
+				"query depth %d exceeds the limit of %d",
+				depth,
+				maxDepth,
+			)
+		}
+
+		if complexity > maxComplexity {
+			return fmt.Errorf(
+				"query complexity %d exceeds the limit of %d",
+				complexity,
+				maxComplexity,
+			)
+		}
+	}
+
+	return nil
+}
+
+type limitsWalker struct {
+	schema    *graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+}
+
+// walk returns the depth and complexity of set selected on parent, the
+// document having been validated already.
+func (w *limitsWalker) walk(
+	set *ast.SelectionSet,
+	parent graphql.Type,
+	depth int,
+) (int, int) {
+	if set == nil {
+		return depth, 0
+	}
+
+	maxDepth, complexity := depth, 0
+
+	for _, sel := range set.Selections {
+		var d, c int
+
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if sel.Name.Value == graphql.TypeNameMetaFieldDef.Name {
+				continue
+			}
+
+			field := w.field(parent, sel.Name.Value)
+			if field == nil {
+				continue
+			}
+
+			d, c = w.walk(sel.SelectionSet, namedType(field.Type), depth+1)
+			if isList(field.Type) {
+				c *= listComplexity
+			}
+			c++
+		case *ast.InlineFragment:
+			typ := parent
+			if sel.TypeCondition != nil {
+				typ = w.schema.Type(sel.TypeCondition.Name.Value)
+			}
+
+			d, c = w.walk(sel.SelectionSet, typ, depth)
+		case *ast.FragmentSpread:
+			frag, ok := w.fragments[sel.Name.Value]
+			if !ok {
+				continue
+			}
+
+			d, c = w.walk(
+				frag.SelectionSet,
+				w.schema.Type(frag.TypeCondition.Name.Value),
+				depth,
+			)
+		}
+
+		if d > maxDepth {
+			maxDepth = d
+		}
+		complexity += c
+	}
+
+	return maxDepth, complexity
+}
+
+// field returns the definition of the named field of parent, including
+// __schema and __type, which only the query root has and which are not
+// among its fields.
+func (w *limitsWalker) field(
+	parent graphql.Type,
+	name string,
+) *graphql.FieldDefinition {
+	if parent == w.schema.QueryType() {
+		switch name {
+		case graphql.SchemaMetaFieldDef.Name:
+			return graphql.SchemaMetaFieldDef
+		case graphql.TypeMetaFieldDef.Name:
+			return graphql.TypeMetaFieldDef
+		}
+	}
+
+	obj, ok := parent.(*graphql.Object)
+	if !ok {
+		return nil
+	}
+
+	return obj.Fields()[name]
+}
+
+func isList(t graphql.Type) bool {
+	if nonNull, ok := t.(*graphql.NonNull); ok {
+		t = nonNull.OfType
+	}
+
+	_, ok := t.(*graphql.List)
+	return ok
+}
+
+func namedType(t graphql.Type) graphql.Type {
+	for {
+		switch wrapped := t.(type) {
+		case *graphql.NonNull:
+			t = wrapped.OfType
+		case *graphql.List:
+			t = wrapped.OfType
+		default:
+			return t
+		}
+	}
+}
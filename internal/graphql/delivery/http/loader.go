@@ -0,0 +1,95 @@
+package http
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/usecases"
+	"github.com/slavtov/clean-architecture/pkg/logger"
+)
+
+// userLoader batches the user lookups of a single request. Load only
+// records the ID and returns a thunk; the executor runs thunks after
+// resolving the rest of their level, so the first one fetches every ID
+// recorded so far with one query.
+type userLoader struct {
+	mu          sync.Mutex
+	userUseCase usecases.UserUseCase
+	pending     map[uuid.UUID]struct{}
+	users       map[uuid.UUID]*models.Profile
+	err         error
+	log         logger.Logger
+}
+
+func newUserLoader(uu usecases.UserUseCase, log logger.Logger) *userLoader {
+	return &userLoader{
+		userUseCase: uu,
+		pending:     make(map[uuid.UUID]struct{}),
+		users:       make(map[uuid.UUID]*models.Profile),
+		log:         log,
+	}
+}
+
+func (l *userLoader) Load(id uuid.UUID) func() (interface{}, error) {
+	l.mu.Lock()
+	if _, ok := l.users[id]; !ok {
+		l.pending[id] = struct{}{}
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		user, err := l.get(id)
+		if err != nil {
+			return nil, resolverError(err)
+		}
+
+		// A nil *models.Profile would not be a null author.
+		if user == nil {
+			return nil, nil
+		}
+
+		return user, nil
+	}
+}
+
+func (l *userLoader) get(id uuid.UUID) (*models.Profile, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.pending) > 0 {
+		l.fetch()
+	}
+
+	if user, ok := l.users[id]; ok {
+		return user, nil
+	}
+
+	return nil, l.err
+}
+
+// fetch loads the pending users. Users that no longer exist are cached
+// as nil so they are not asked for again.
+func (l *userLoader) fetch() {
+	ids := make([]uuid.UUID, 0, len(l.pending))
+	for id := range l.pending {
+		ids = append(ids, id)
+	}
+	l.pending = make(map[uuid.UUID]struct{})
+
+	res, err := l.userUseCase.GetByIDs(ids)
+	if err != nil {
+		l.log.Errorf("auth.UseCase.GetByIDs: %v", err)
+		l.err = err
+		return
+	}
+
+	for _, id := range ids {
+		l.users[id] = nil
+	}
+
+	for i := range res {
+		p := res[i].Profile()
+		l.users[p.ID] = &p
+	}
+}
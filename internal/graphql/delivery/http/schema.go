@@ -0,0 +1,461 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+)
+
+type ctxKey int
+
+const (
+	viewerKey ctxKey = iota
+	loaderKey
+)
+
+func viewerID(ctx context.Context) uuid.UUID {
+	id, _ := ctx.Value(viewerKey).(uuid.UUID)
+	return id
+}
+
+func loader(ctx context.Context) *userLoader {
+	return ctx.Value(loaderKey).(*userLoader)
+}
+
+// gqlError is reported with a machine readable code in the error's
+// extensions.
+type gqlError struct {
+	code    string
+	message string
+}
+
+func (e *gqlError) Error() string {
+	return e.message
+}
+
+func (e *gqlError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+var errorCodes = map[int]string{
+	http.StatusBadRequest:         "BAD_USER_INPUT",
+	http.StatusUnauthorized:       "UNAUTHENTICATED",
+	http.StatusForbidden:          "FORBIDDEN",
+	http.StatusNotFound:           "NOT_FOUND",
+	http.StatusConflict:           "CONFLICT",
+	http.StatusPreconditionFailed: "PRECONDITION_FAILED",
+}
+
+// resolverError turns the echo errors returned by usecases into GraphQL
+// errors. Unexpected errors are reported without details, like the HTTP
+// error handler does.
+func resolverError(err error) error {
+	he, ok := err.(*echo.HTTPError)
+	if !ok {
+		return &gqlError{
+			code:    "INTERNAL_SERVER_ERROR",
+			message: http.StatusText(http.StatusInternalServerError),
+		}
+	}
+
+	code, ok := errorCodes[he.Code]
+	if !ok {
+		code = "INTERNAL_SERVER_ERROR"
+	}
+
+	return &gqlError{code: code, message: fmt.Sprint(he.Message)}
+}
+
+func (h *handler) resolve(fn graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		res, err := fn(p)
+		if err != nil {
+			h.log.Errorf("graphql.%s: %v", p.Info.FieldName, err)
+			return nil, resolverError(err)
+		}
+
+		return res, nil
+	}
+}
+
+func (h *handler) newSchema() (graphql.Schema, error) {
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "User",
+		Description: "The public profile of a user.",
+		Fields: graphql.Fields{
+			"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"handle":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"displayName": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"bio":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"avatarUrl":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"createdAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+		},
+	})
+
+	articleType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Article",
+		Fields: graphql.Fields{
+			"id":       &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"authorId": &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"author": &graphql.Field{
+				Type:        userType,
+				Description: "Null when the author's account has been deleted.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					a := p.Source.(*models.Article)
+					return loader(p.Context).Load(a.AuthorID), nil
+				},
+			},
+			"title":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"slug":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"desc":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"descHtml":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"format":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"status":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"publishedAt": &graphql.Field{Type: graphql.DateTime},
+			"tags":        &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+			"likesCount":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"liked": &graphql.Field{
+				Type:        graphql.Boolean,
+				Description: "Whether the viewer likes the article, null for anonymous requests.",
+			},
+			"bookmarked": &graphql.Field{
+				Type:        graphql.Boolean,
+				Description: "Whether the viewer bookmarked the article, null for anonymous requests.",
+			},
+			"updatedAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+		},
+	})
+
+	articlesField := &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(articleType))),
+		Description: "Published articles and the viewer's own unpublished ones.",
+		Args: graphql.FieldConfigArgument{
+			"tags": &graphql.ArgumentConfig{
+				Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+				Description: "Only articles with any of these tags.",
+			},
+		},
+		Resolve: h.resolve(h.articles),
+	}
+
+	userType.AddFieldConfig("articles", articlesField)
+
+	viewerType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Viewer",
+		Description: "The authenticated user.",
+		Fields: graphql.Fields{
+			"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"email":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"role":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"handle":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"displayName": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"bio":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"avatarUrl":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"articles":    articlesField,
+			"updatedAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"createdAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+		},
+	})
+
+	articleInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "ArticleInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":       &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"desc":        &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"format":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"status":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"publishedAt": &graphql.InputObjectFieldConfig{Type: graphql.DateTime},
+			"tags":        &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"articles": articlesField,
+			"article": &graphql.Field{
+				Type: articleType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{
+						Type:        graphql.NewNonNull(graphql.ID),
+						Description: "Article ID or slug.",
+					},
+				},
+				Resolve: h.resolve(h.article),
+			},
+			"users": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(userType))),
+				Resolve: h.resolve(h.users),
+			},
+			"user": &graphql.Field{
+				Type: userType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{
+						Type:        graphql.NewNonNull(graphql.ID),
+						Description: "User ID or handle.",
+					},
+				},
+				Resolve: h.resolve(h.user),
+			},
+			"viewer": &graphql.Field{
+				Type:        viewerType,
+				Description: "Null for anonymous requests.",
+				Resolve:     h.resolve(h.viewer),
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createArticle": &graphql.Field{
+				Type: graphql.NewNonNull(articleType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(articleInput)},
+				},
+				Resolve: h.resolve(h.createArticle),
+			},
+			"updateArticle": &graphql.Field{
+				Type: graphql.NewNonNull(articleType),
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(articleInput)},
+					"updatedAt": &graphql.ArgumentConfig{
+						Type:        graphql.DateTime,
+						Description: "The updatedAt of the article being edited, to detect concurrent changes.",
+					},
+				},
+				Resolve: h.resolve(h.updateArticle),
+			},
+			"deleteArticle": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: h.resolve(h.deleteArticle),
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: mutation,
+	})
+}
+
+// articles resolves Query.articles and, with the user as the source,
+// User.articles and Viewer.articles.
+func (h *handler) articles(p graphql.ResolveParams) (interface{}, error) {
+	filter := &models.ArticleFilter{
+		ViewerID: viewerID(p.Context),
+		Tags:     models.NormalizeTags(stringList(p.Args["tags"])),
+	}
+
+	switch src := p.Source.(type) {
+	case *models.Profile:
+		filter.AuthorID = &src.ID
+	case *models.User:
+		filter.AuthorID = &src.ID
+	}
+
+	res, err := h.articleUseCase.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	articles := make([]*models.Article, 0, len(res))
+	for i := range res {
+		articles = append(articles, &res[i])
+	}
+
+	return articles, nil
+}
+
+func (h *handler) article(p graphql.ResolveParams) (interface{}, error) {
+	var (
+		article models.Article
+		err     error
+	)
+
+	param := p.Args["id"].(string)
+
+	if id, parseErr := uuid.Parse(param); parseErr == nil {
+		article, err = h.articleUseCase.GetByID(id, viewerID(p.Context))
+	} else {
+		article, err = h.articleUseCase.GetBySlug(param, viewerID(p.Context))
+	}
+
+	if err != nil {
+		if he, ok := err.(*echo.HTTPError); ok && he.Code == http.StatusNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &article, nil
+}
+
+func (h *handler) users(p graphql.ResolveParams) (interface{}, error) {
+	res, err := h.userUseCase.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]*models.Profile, 0, len(res))
+	for i := range res {
+		profile := res[i].Profile()
+		profiles = append(profiles, &profile)
+	}
+
+	return profiles, nil
+}
+
+func (h *handler) user(p graphql.ResolveParams) (interface{}, error) {
+	var (
+		user models.User
+		err  error
+	)
+
+	param := p.Args["id"].(string)
+
+	if id, parseErr := uuid.Parse(param); parseErr == nil {
+		user, err = h.userUseCase.GetByID(id)
+	} else {
+		user, err = h.userUseCase.GetByHandle(param)
+	}
+
+	if err != nil {
+		if he, ok := err.(*echo.HTTPError); ok && he.Code == http.StatusNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	profile := user.Profile()
+
+	return &profile, nil
+}
+
+func (h *handler) viewer(p graphql.ResolveParams) (interface{}, error) {
+	id := viewerID(p.Context)
+	if id == uuid.Nil {
+		return nil, nil
+	}
+
+	user, err := h.userUseCase.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (h *handler) createArticle(p graphql.ResolveParams) (interface{}, error) {
+	id := viewerID(p.Context)
+	if id == uuid.Nil {
+		return nil, echo.ErrUnauthorized
+	}
+
+	a := articleInput(p.Args["input"])
+	a.AuthorID = id
+
+	return h.articleUseCase.Store(a)
+}
+
+func (h *handler) updateArticle(p graphql.ResolveParams) (interface{}, error) {
+	userID := viewerID(p.Context)
+	if userID == uuid.Nil {
+		return nil, echo.ErrUnauthorized
+	}
+
+	id, err := uuid.Parse(p.Args["id"].(string))
+	if err != nil {
+		return nil, echo.ErrNotFound
+	}
+
+	a := articleInput(p.Args["input"])
+	a.ID = id
+	a.AuthorID = userID
+
+	if updatedAt, ok := p.Args["updatedAt"]; ok {
+		t, ok := updatedAt.(time.Time)
+		if !ok {
+			return nil, repositories.ErrArticleConflict
+		}
+
+		a.UpdatedAt = t
+	}
+
+	return h.articleUseCase.Update(a)
+}
+
+func (h *handler) deleteArticle(p graphql.ResolveParams) (interface{}, error) {
+	userID := viewerID(p.Context)
+	if userID == uuid.Nil {
+		return nil, echo.ErrUnauthorized
+	}
+
+	id, err := uuid.Parse(p.Args["id"].(string))
+	if err != nil {
+		return nil, echo.ErrNotFound
+	}
+
+	if err := h.articleUseCase.Delete(models.Article{
+		ID:       id,
+		AuthorID: userID,
+	}); err != nil {
+		return nil, err
+	}
+
+	return true, nil
+}
+
+func articleInput(arg interface{}) *models.Article {
+	input, _ := arg.(map[string]interface{})
+
+	a := &models.Article{
+		Tags: stringList(input["tags"]),
+	}
+	a.Title, _ = input["title"].(string)
+	a.Desc, _ = input["desc"].(string)
+
+	if format, ok := input["format"].(string); ok {
+		a.Format = models.ArticleFormat(format)
+	}
+
+	if status, ok := input["status"].(string); ok {
+		a.Status = models.ArticleStatus(status)
+	}
+
+	if publishedAt, ok := input["publishedAt"].(time.Time); ok {
+		a.PublishedAt = &publishedAt
+	}
+
+	return a
+}
+
+func stringList(arg interface{}) []string {
+	list, _ := arg.([]interface{})
+	if len(list) == 0 {
+		return nil
+	}
+
+	res := make([]string, 0, len(list))
+	for _, v := range list {
+		if s, ok := v.(string); ok {
+			res = append(res, s)
+		}
+	}
+
+	return res
+}
@@ -18,35 +18,33 @@ func Auth(
 ) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			if err := verifyAccessToken(cfg, c, log); err != nil {
-				log.Errorf("verifyAccessToken: %v", err)
+			if err := authenticate(cfg, c, userUseCase, log); err != nil {
 				return echo.ErrUnauthorized
 			}
 
-			if err := utils.VerifyRefreshToken(
-				c,
-				cfg.Server.JwtRefreshSecret,
-				log,
-			); err != nil {
-				log.Errorf("verifyRefreshToken: %v", err)
-				return echo.ErrUnauthorized
+			return next(c)
+		}
+	}
+}
+
+// OptionalAuth identifies the user when the request carries credentials
+// and lets anonymous requests, or ones with invalid credentials, through
+// without a user in the context.
+func OptionalAuth(
+	cfg *config.Config,
+	userUseCase usecases.UserUseCase,
+	log logger.Logger,
+) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if isAnonymous(c) {
+				return next(c)
 			}
 
-			userID := utils.GetCtxID(c)
-			accessID := utils.GetCtxAccessID(c)
-			refreshID := utils.GetCtxRefreshID(c)
-
-			if err := verifyRedis(
-				userID,
-				userUseCase,
-				&utils.TokenDetails{
-					AtID: accessID,
-					RtID: refreshID,
-				},
-				log,
-			); err != nil {
-				log.Errorf("verifyRedis: %v", err)
-				return echo.ErrUnauthorized
+			if err := authenticate(cfg, c, userUseCase, log); err != nil {
+				c.Set("user_id", nil)
+				c.Set("access_id", nil)
+				c.Set("refresh_id", nil)
 			}
 
 			return next(c)
@@ -54,6 +52,46 @@ func Auth(
 	}
 }
 
+func authenticate(
+	cfg *config.Config,
+	c echo.Context,
+	userUseCase usecases.UserUseCase,
+	log logger.Logger,
+) error {
+	if err := verifyAccessToken(cfg, c, log); err != nil {
+		log.Errorf("verifyAccessToken: %v", err)
+		return err
+	}
+
+	if err := utils.VerifyRefreshToken(
+		c,
+		cfg.Server.JwtRefreshSecret,
+		log,
+	); err != nil {
+		log.Errorf("verifyRefreshToken: %v", err)
+		return err
+	}
+
+	userID := utils.GetCtxID(c)
+	accessID := utils.GetCtxAccessID(c)
+	refreshID := utils.GetCtxRefreshID(c)
+
+	if err := verifyRedis(
+		userID,
+		userUseCase,
+		&utils.TokenDetails{
+			AtID: accessID,
+			RtID: refreshID,
+		},
+		log,
+	); err != nil {
+		log.Errorf("verifyRedis: %v", err)
+		return err
+	}
+
+	return nil
+}
+
 func verifyAccessToken(
 	cfg *config.Config,
 	c echo.Context,
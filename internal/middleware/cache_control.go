@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/config"
+)
+
+const HeaderCacheControl = "Cache-Control"
+
+func CacheControl(cfg *config.Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Response().Header()
+
+			// The response depends on who asks, so shared caches must not
+			// hand the anonymous copy to a logged in user.
+			header.Add(echo.HeaderVary, echo.HeaderAuthorization+", Cookie")
+
+			if isAnonymous(c) && cfg.HTTPCache.CacheControl != "" {
+				header.Set(HeaderCacheControl, cfg.HTTPCache.CacheControl)
+			} else {
+				header.Set(HeaderCacheControl, "private, no-cache")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func isAnonymous(c echo.Context) bool {
+	if c.Request().Header.Get(echo.HeaderAuthorization) != "" {
+		return false
+	}
+
+	if _, err := c.Cookie("access_token"); err == nil {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/config"
+)
+
+func TestCacheControl(t *testing.T) {
+	cfg := &config.Config{HTTPCache: config.HTTPCacheConfig{
+		CacheControl: "public, max-age=60",
+	}}
+
+	tests := []struct {
+		name         string
+		header       string
+		value        string
+		cacheControl string
+	}{
+		{"anonymous", "", "", "public, max-age=60"},
+		{"bearer token", echo.HeaderAuthorization, "Bearer token", "private, no-cache"},
+		{"cookie", "Cookie", "access_token=token", "private, no-cache"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/articles", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+
+			if err := CacheControl(cfg)(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := rec.Header().Get(HeaderCacheControl); got != tt.cacheControl {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.cacheControl)
+			}
+
+			if got := rec.Header().Get(echo.HeaderVary); got != "Authorization, Cookie" {
+				t.Errorf("Vary = %q, want %q", got, "Authorization, Cookie")
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/config"
+	"github.com/slavtov/clean-architecture/internal/domain/usecases"
+	"github.com/slavtov/clean-architecture/pkg/logger"
+	"github.com/slavtov/clean-architecture/pkg/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GrpcRefreshTokenHeader is the metadata key carrying the refresh token,
+// the gRPC counterpart of the refresh_token cookie.
+const GrpcRefreshTokenHeader = "x-refresh-token"
+
+// GrpcAuth is the gRPC counterpart of Auth and OptionalAuth. Calls to the
+// methods listed in optional may be anonymous and keep going without a
+// user when their credentials are invalid; every other method requires
+// valid credentials.
+func GrpcAuth(
+	cfg *config.Config,
+	userUseCase usecases.UserUseCase,
+	log logger.Logger,
+	optional ...string,
+) grpc.UnaryServerInterceptor {
+	isOptional := make(map[string]bool, len(optional))
+	for _, m := range optional {
+		isOptional[m] = true
+	}
+
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		if isOptional[info.FullMethod] {
+			if len(md.Get("authorization")) == 0 &&
+				len(md.Get(GrpcRefreshTokenHeader)) == 0 {
+				return handler(ctx, req)
+			}
+
+			if authCtx, err := authenticateGrpc(
+				ctx,
+				md,
+				cfg,
+				userUseCase,
+				log,
+			); err == nil {
+				ctx = authCtx
+			}
+
+			return handler(ctx, req)
+		}
+
+		authCtx, err := authenticateGrpc(ctx, md, cfg, userUseCase, log)
+		if err != nil {
+			return nil, status.Error(
+				codes.Unauthenticated,
+				http.StatusText(http.StatusUnauthorized),
+			)
+		}
+
+		return handler(authCtx, req)
+	}
+}
+
+func authenticateGrpc(
+	ctx context.Context,
+	md metadata.MD,
+	cfg *config.Config,
+	userUseCase usecases.UserUseCase,
+	log logger.Logger,
+) (context.Context, error) {
+	accessID, userID, err := utils.ParseToken(
+		"access",
+		bearerToken(md),
+		cfg.Server.JwtSecret,
+	)
+	if err != nil {
+		log.Errorf("verifyAccessToken: %v", err)
+		return nil, err
+	}
+
+	refreshID, refreshUserID, err := utils.ParseToken(
+		"refresh",
+		firstValue(md, GrpcRefreshTokenHeader),
+		cfg.Server.JwtRefreshSecret,
+	)
+	if err != nil {
+		log.Errorf("verifyRefreshToken: %v", err)
+		return nil, err
+	}
+
+	if refreshUserID != userID {
+		return nil, errors.New("tokens belong to different users")
+	}
+
+	td := &utils.TokenDetails{
+		AtID: accessID,
+		RtID: refreshID,
+	}
+
+	if err := verifyRedis(userID, userUseCase, td, log); err != nil {
+		log.Errorf("verifyRedis: %v", err)
+		return nil, err
+	}
+
+	return utils.SetGrpcAuth(ctx, userID, td), nil
+}
+
+func bearerToken(md metadata.MD) string {
+	arr := strings.Split(firstValue(md, "authorization"), " ")
+	if len(arr) != 2 || arr[0] != "Bearer" {
+		return ""
+	}
+
+	return arr[1]
+}
+
+func firstValue(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// GrpcErrors turns the echo errors returned by usecases into gRPC
+// statuses. Unexpected errors are reported as Internal without details,
+// like the HTTP error handler does.
+func GrpcErrors(log logger.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		res, err := handler(ctx, req)
+		if err == nil {
+			return res, nil
+		}
+
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+
+		var he *echo.HTTPError
+		if !errors.As(err, &he) {
+			log.Errorf("%s: %v", info.FullMethod, err)
+			return nil, status.Error(
+				codes.Internal,
+				http.StatusText(http.StatusInternalServerError),
+			)
+		}
+
+		return nil, status.Error(grpcCode(he.Code), fmt.Sprint(he.Message))
+	}
+}
+
+func grpcCode(httpCode int) codes.Code {
+	switch httpCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusRequestEntityTooLarge, http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
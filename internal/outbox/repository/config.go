@@ -0,0 +1,30 @@
+package repository
+
+var (
+	createEventQuery = `INSERT INTO outbox (type, aggregate_id, payload) 
+								VALUES ($1, $2, $3) RETURNING *`
+	leaseEventsQuery = `WITH due AS (
+									SELECT id FROM outbox 
+									WHERE status = 'pending' AND available_at <= now() 
+									ORDER BY id LIMIT $1 
+									FOR UPDATE SKIP LOCKED
+								) 
+								UPDATE outbox SET available_at = now() + make_interval(secs => $2) 
+								FROM due WHERE outbox.id = due.id 
+								RETURNING outbox.*`
+	getDeadEventsQuery = `SELECT * FROM outbox 
+								WHERE status = 'dead' ORDER BY id`
+	markPublishedQuery = `UPDATE outbox 
+								SET status = 'published', attempts = attempts + 1, 
+								last_error = NULL, published_at = now() 
+								WHERE id = $1`
+	markFailedQuery = `UPDATE outbox 
+								SET status = $2, attempts = attempts + 1, 
+								last_error = $3, available_at = $4 
+								WHERE id = $1`
+	requeueEventQuery = `UPDATE outbox 
+								SET status = 'pending', attempts = 0, available_at = now() 
+								WHERE id = $1 AND status = 'dead'`
+	purgeEventsQuery = `DELETE FROM outbox 
+								WHERE status = 'published' AND published_at < $1`
+)
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/pkg/logger"
+)
+
+type logSink struct {
+	log logger.Logger
+}
+
+// NewLogSink returns a sink that only writes events to the log, for
+// local development.
+func NewLogSink(log logger.Logger) repositories.EventSink {
+	return &logSink{log}
+}
+
+func (s *logSink) Publish(e *models.Event) error {
+	s.log.Infof(
+		"outbox: event %d %s %s: %s",
+		e.ID,
+		e.Type,
+		e.AggregateID,
+		e.Payload,
+	)
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+)
+
+type multiSink struct {
+	sinks []repositories.EventSink
+}
+
+// NewMultiSink returns a sink that publishes every event to all sinks.
+// When one fails the event is retried on all of them, so each sink has
+// to cope with duplicates anyway.
+func NewMultiSink(sinks ...repositories.EventSink) repositories.EventSink {
+	return &multiSink{sinks}
+}
+
+func (s *multiSink) Publish(e *models.Event) error {
+	for _, sink := range s.sinks {
+		if err := sink.Publish(e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
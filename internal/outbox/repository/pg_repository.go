@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/pkg/store/postgres"
+)
+
+type pgRepository struct {
+	db postgres.DB
+}
+
+func NewPGRepository(db postgres.DB) repositories.PGOutboxRepository {
+	return &pgRepository{db}
+}
+
+func (r *pgRepository) Store(e *models.Event) (*models.Event, error) {
+	var event models.Event
+
+	if err := r.db.QueryRowx(
+		createEventQuery,
+		e.Type,
+		e.AggregateID,
+		e.Payload,
+	).StructScan(&event); err != nil {
+		return nil, echo.ErrInternalServerError
+	}
+
+	return &event, nil
+}
+
+// Lease claims the next pending events that are due by pushing their
+// available_at the lease duration ahead, so other relays leave them alone
+// without a lock being held while they are published. Events the relay
+// does not get to mark in time become due again.
+func (r *pgRepository) Lease(
+	limit int,
+	lease time.Duration,
+) ([]models.Event, error) {
+	var events []models.Event
+
+	if err := r.db.Select(
+		&events,
+		leaseEventsQuery,
+		limit,
+		lease.Seconds(),
+	); err != nil {
+		return events, echo.ErrInternalServerError
+	}
+
+	// UPDATE ... RETURNING does not keep the order of the subquery.
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+
+	return events, nil
+}
+
+func (r *pgRepository) GetDead() ([]models.Event, error) {
+	var events []models.Event
+
+	if err := r.db.Select(
+		&events,
+		getDeadEventsQuery,
+	); err != nil {
+		return events, echo.ErrInternalServerError
+	}
+
+	return events, nil
+}
+
+func (r *pgRepository) MarkPublished(id int64) error {
+	if _, err := r.db.Exec(markPublishedQuery, id); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (r *pgRepository) MarkFailed(
+	id int64,
+	status string,
+	reason string,
+	retryAt time.Time,
+) error {
+	if _, err := r.db.Exec(
+		markFailedQuery,
+		id,
+		status,
+		reason,
+		retryAt,
+	); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (r *pgRepository) Requeue(id int64) error {
+	res, err := r.db.Exec(requeueEventQuery, id)
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	if rowsAffected == 0 {
+		return echo.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *pgRepository) Purge(before time.Time) (int64, error) {
+	res, err := r.db.Exec(purgeEventsQuery, before)
+	if err != nil {
+		return 0, echo.ErrInternalServerError
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, echo.ErrInternalServerError
+	}
+
+	return n, nil
+}
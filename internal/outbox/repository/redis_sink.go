@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/pkg/store/redis"
+)
+
+type redisSink struct {
+	db     redis.Store
+	stream string
+	maxLen int64
+}
+
+// NewRedisSink returns a sink that appends events to a Redis Stream,
+// which keeps about maxLen of the latest events.
+func NewRedisSink(
+	db redis.Store,
+	stream string,
+	maxLen int64,
+) repositories.EventSink {
+	return &redisSink{db, stream, maxLen}
+}
+
+func (s *redisSink) Publish(e *models.Event) error {
+	_, err := s.db.XAdd(s.stream, s.maxLen, map[string]interface{}{
+		"id":           strconv.FormatInt(e.ID, 10),
+		"type":         e.Type,
+		"aggregate_id": e.AggregateID.String(),
+		"payload":      string(e.Payload),
+		"created_at":   e.CreatedAt.Format(time.RFC3339Nano),
+	})
+
+	return err
+}
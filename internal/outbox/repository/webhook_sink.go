@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/pkg/webhook"
+)
+
+type webhookSink struct {
+	client *webhook.Client
+	url    string
+	secret string
+}
+
+// NewWebhookSink returns a sink that posts every event to one URL. The
+// event ID is sent along so the receiver can drop redeliveries.
+func NewWebhookSink(
+	client *webhook.Client,
+	url string,
+	secret string,
+) repositories.EventSink {
+	return &webhookSink{client, url, secret}
+}
+
+func (s *webhookSink) Publish(e *models.Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Send(s.url, s.secret, map[string]string{
+		"X-Event-ID":   strconv.FormatInt(e.ID, 10),
+		"X-Event-Type": e.Type,
+	}, body)
+
+	return err
+}
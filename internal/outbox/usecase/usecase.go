@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/slavtov/clean-architecture/internal/config"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/internal/domain/usecases"
+	"github.com/slavtov/clean-architecture/pkg/logger"
+	"github.com/slavtov/clean-architecture/pkg/utils"
+)
+
+type usecase struct {
+	cfg          *config.Config
+	pgRepository repositories.PGOutboxRepository
+	sink         repositories.EventSink
+	log          logger.Logger
+}
+
+const maxErrorLength = 1000
+
+func New(
+	cfg *config.Config,
+	pg repositories.PGOutboxRepository,
+	sink repositories.EventSink,
+	log logger.Logger,
+) usecases.OutboxUseCase {
+	return &usecase{
+		cfg:          cfg,
+		pgRepository: pg,
+		sink:         sink,
+		log:          log,
+	}
+}
+
+// Relay publishes the pending events that are due and returns how many
+// were published. The batch is leased first and published outside of any
+// transaction, so a slow sink holds no row locks. An event is only marked
+// published once the sink has accepted it; one the process dies in
+// between, or that is not marked before the lease runs out, is published
+// again.
+func (u *usecase) Relay(limit int) (int, error) {
+	events, err := u.pgRepository.Lease(
+		limit,
+		time.Second*time.Duration(u.cfg.Outbox.Lease),
+	)
+	if err != nil {
+		u.log.Errorf("outbox.pgRepository.Lease: %v", err)
+		return 0, err
+	}
+
+	published := 0
+
+	for i := range events {
+		e := &events[i]
+
+		if err := u.sink.Publish(e); err != nil {
+			u.log.Errorf("outbox.sink.Publish: event %d: %v", e.ID, err)
+
+			status, retryAt := u.retry(e)
+			if status == models.EventDead {
+				u.log.Errorf(
+					"outbox.Relay: event %d is dead after %d attempts",
+					e.ID,
+					e.Attempts+1,
+				)
+			}
+
+			if err := u.pgRepository.MarkFailed(
+				e.ID,
+				status,
+				utils.Truncate(err.Error(), maxErrorLength),
+				retryAt,
+			); err != nil {
+				u.log.Errorf("outbox.pgRepository.MarkFailed: %v", err)
+				return published, err
+			}
+
+			continue
+		}
+
+		if err := u.pgRepository.MarkPublished(e.ID); err != nil {
+			u.log.Errorf("outbox.pgRepository.MarkPublished: %v", err)
+			return published, err
+		}
+
+		published++
+	}
+
+	return published, nil
+}
+
+func (u *usecase) GetDead() ([]models.Event, error) {
+	res, err := u.pgRepository.GetDead()
+	if err != nil {
+		u.log.Errorf("outbox.pgRepository.GetDead: %v", err)
+		return res, err
+	}
+
+	return res, nil
+}
+
+// Requeue gives a dead event a fresh set of attempts.
+func (u *usecase) Requeue(id int64) error {
+	if err := u.pgRepository.Requeue(id); err != nil {
+		u.log.Errorf("outbox.pgRepository.Requeue: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Purge removes the events published before the given time. Dead events
+// are kept until they are requeued and published.
+func (u *usecase) Purge(before time.Time) (int64, error) {
+	n, err := u.pgRepository.Purge(before)
+	if err != nil {
+		u.log.Errorf("outbox.pgRepository.Purge: %v", err)
+		return 0, err
+	}
+
+	if n > 0 {
+		u.log.Infof("outbox.Purge: %d events purged", n)
+	}
+
+	return n, nil
+}
+
+// retry returns the state of the event after a failed attempt: pending
+// again after an exponential backoff, or dead once it has run out of
+// attempts.
+func (u *usecase) retry(e *models.Event) (string, time.Time) {
+	attempts := e.Attempts + 1
+	if attempts >= u.cfg.Outbox.MaxAttempts {
+		return models.EventDead, time.Now()
+	}
+
+	backoff := utils.Backoff(
+		time.Second*time.Duration(u.cfg.Outbox.RetryBackoff),
+		time.Second*time.Duration(u.cfg.Outbox.MaxRetryBackoff),
+		attempts,
+	)
+
+	return models.EventPending, time.Now().Add(backoff)
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	articleDelivery "github.com/slavtov/clean-architecture/internal/article/delivery/grpc"
+	authDelivery "github.com/slavtov/clean-architecture/internal/auth/delivery/grpc"
+	"github.com/slavtov/clean-architecture/internal/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+func (s *Server) grpcServer() *grpc.Server {
+	authUC := s.app.Users
+	articleUC := s.app.Articles
+
+	var optional []string
+	optional = append(optional, authDelivery.OptionalAuthMethods...)
+	optional = append(optional, articleDelivery.OptionalAuthMethods...)
+
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		middleware.GrpcErrors(s.log),
+		middleware.GrpcAuth(s.cfg, authUC, s.log, optional...),
+	))
+
+	authDelivery.Init(s.cfg, srv, authUC, s.log)
+	articleDelivery.Init(srv, articleUC, s.log)
+
+	if s.cfg.Server.GrpcReflection {
+		reflection.Register(srv)
+	}
+
+	return srv
+}
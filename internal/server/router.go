@@ -1,37 +1,123 @@
 package server
 
 import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	articleDelivery "github.com/slavtov/clean-architecture/internal/article/delivery/http"
-	articleRepository "github.com/slavtov/clean-architecture/internal/article/repository"
-	articleUseCase "github.com/slavtov/clean-architecture/internal/article/usecase"
+	attachmentDelivery "github.com/slavtov/clean-architecture/internal/attachment/delivery/http"
 	authDelivery "github.com/slavtov/clean-architecture/internal/auth/delivery/http"
-	authRepository "github.com/slavtov/clean-architecture/internal/auth/repository"
-	authUseCase "github.com/slavtov/clean-architecture/internal/auth/usecase"
+	commentDelivery "github.com/slavtov/clean-architecture/internal/comment/delivery/http"
+	feedDelivery "github.com/slavtov/clean-architecture/internal/feed/delivery/http"
+	graphqlDelivery "github.com/slavtov/clean-architecture/internal/graphql/delivery/http"
+	webhookDelivery "github.com/slavtov/clean-architecture/internal/webhook/delivery/http"
 	echoSwagger "github.com/swaggo/echo-swagger"
 )
 
 func (s *Server) middleware() {
+	// Client IPs, e.g. in the audit log, come from the connection unless
+	// the server is known to sit behind a proxy setting X-Forwarded-For.
+	s.router.IPExtractor = echo.ExtractIPDirect()
+	if s.cfg.Server.TrustProxy {
+		s.router.IPExtractor = echo.ExtractIPFromXFFHeader()
+	}
+
 	s.router.Pre(middleware.RemoveTrailingSlash())
 	s.router.Use(middleware.CORS())
 }
 
 func (s *Server) handlers() {
-	authRepo := authRepository.NewPGRepository(s.db)
-	authRedisRepo := authRepository.NewRedisRepository(s.redis)
-	articleRepo := articleRepository.NewPGRepository(s.db)
-	articleRedisRepo := articleRepository.NewRedisRepository(s.redis)
+	authUC := s.app.Users
+	articleUC := s.app.Articles
+	commentUC := s.app.Comments
+	attachmentUC := s.app.Attachments
+	outboxUC := s.app.Outbox
+	webhookUC := s.app.Webhooks
+	feedUC := s.app.Feed
 
-	authUC := authUseCase.New(
-		s.cfg,
-		authRepo,
-		authRedisRepo,
-		s.log,
+	s.schedule(
+		"purge",
+		time.Second*time.Duration(s.cfg.SoftDelete.PurgeInterval),
+		func() error {
+			before := time.Now().Add(
+				-time.Second * time.Duration(s.cfg.SoftDelete.Retention),
+			)
+
+			if _, err := commentUC.Purge(before); err != nil {
+				return err
+			}
+
+			if _, err := attachmentUC.Purge(before); err != nil {
+				return err
+			}
+
+			if _, err := articleUC.Purge(before); err != nil {
+				return err
+			}
+
+			if _, err := authUC.Purge(time.Now()); err != nil {
+				return err
+			}
+
+			if _, err := outboxUC.Purge(time.Now().Add(
+				-time.Second * time.Duration(s.cfg.Outbox.Retention),
+			)); err != nil {
+				return err
+			}
+
+			_, err := webhookUC.Purge(time.Now().Add(
+				-time.Second * time.Duration(s.cfg.Webhooks.Retention),
+			))
+			return err
+		},
 	)
-	articleUC := articleUseCase.New(
-		articleRepo,
-		articleRedisRepo,
-		s.log,
+
+	s.schedule(
+		"publish",
+		time.Second*time.Duration(s.cfg.Scheduler.PublishInterval),
+		func() error {
+			_, err := articleUC.PublishDue(s.cfg.Scheduler.PublishBatchSize)
+			return err
+		},
+	)
+
+	s.schedule(
+		"likes",
+		time.Second*time.Duration(s.cfg.Scheduler.LikesInterval),
+		func() error {
+			_, err := articleUC.ReconcileLikes()
+			return err
+		},
+	)
+
+	s.schedule(
+		"outbox",
+		time.Second*time.Duration(s.cfg.Outbox.Interval),
+		func() error {
+			// Keep going while there is a backlog instead of waiting for
+			// the next tick after every batch.
+			for {
+				n, err := outboxUC.Relay(s.cfg.Outbox.BatchSize)
+				if err != nil || n < s.cfg.Outbox.BatchSize {
+					return err
+				}
+			}
+		},
+	)
+
+	s.schedule(
+		"webhooks",
+		time.Second*time.Duration(s.cfg.Webhooks.Interval),
+		func() error {
+			for {
+				n, err := webhookUC.Deliver(s.cfg.Webhooks.BatchSize)
+				if err != nil || n < s.cfg.Webhooks.BatchSize {
+					return err
+				}
+			}
+		},
 	)
 
 	if s.cfg.Server.Debug {
@@ -53,4 +139,45 @@ func (s *Server) handlers() {
 		authUC,
 		s.log,
 	)
+	commentDelivery.Init(
+		s.cfg,
+		api,
+		commentUC,
+		authUC,
+		s.log,
+	)
+	attachmentDelivery.Init(
+		s.cfg,
+		api,
+		attachmentUC,
+		authUC,
+		s.log,
+	)
+	webhookDelivery.Init(
+		s.cfg,
+		api,
+		webhookUC,
+		authUC,
+		s.log,
+	)
+	// Feed streams never finish on their own, so they are ended as soon
+	// as the shutdown starts instead of holding it up until the timeout.
+	streams, endStreams := context.WithCancel(context.Background())
+	s.router.Server.RegisterOnShutdown(endStreams)
+
+	feedDelivery.Init(
+		s.cfg,
+		streams,
+		api,
+		feedUC,
+		authUC,
+		s.log,
+	)
+	graphqlDelivery.Init(
+		s.cfg,
+		api,
+		articleUC,
+		authUC,
+		s.log,
+	)
 }
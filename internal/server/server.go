@@ -1,40 +1,158 @@
 package server
 
 import (
-	"github.com/jmoiron/sqlx"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
 	"github.com/labstack/echo/v4"
 	_ "github.com/slavtov/clean-architecture/docs"
+	"github.com/slavtov/clean-architecture/internal/app"
 	"github.com/slavtov/clean-architecture/internal/config"
 	"github.com/slavtov/clean-architecture/pkg/logger"
-	"github.com/slavtov/clean-architecture/pkg/store/redis"
+	"google.golang.org/grpc"
 )
 
 type Server struct {
 	cfg    *config.Config
 	router *echo.Echo
-	db     *sqlx.DB
-	redis  redis.Store
+	grpc   *grpc.Server
+	app    *app.App
 	log    logger.Logger
+	stop   chan struct{}
+	jobs   sync.WaitGroup
 }
 
 func New(
 	cfg *config.Config,
-	db *sqlx.DB,
-	rdb redis.Store,
+	a *app.App,
 	log logger.Logger,
 ) *Server {
 	return &Server{
 		cfg:    cfg,
 		router: echo.New(),
-		db:     db,
-		redis:  rdb,
+		app:    a,
 		log:    log,
+		stop:   make(chan struct{}),
 	}
 }
 
+// Run serves HTTP and, when server.grpc_addr is set, gRPC until either
+// fails or the process receives SIGINT or SIGTERM, then shuts both down
+// gracefully.
 func (s *Server) Run() error {
 	s.middleware()
 	s.handlers()
 
-	return s.router.Start(s.cfg.Server.Addr)
+	errs := make(chan error, 2)
+
+	go func() {
+		if err := s.router.Start(s.cfg.Server.Addr); err != nil &&
+			!errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
+	}()
+
+	if s.cfg.Server.GrpcAddr != "" {
+		lis, err := net.Listen("tcp", s.cfg.Server.GrpcAddr)
+		if err != nil {
+			s.shutdown()
+			return err
+		}
+
+		s.grpc = s.grpcServer()
+		s.log.Infof("gRPC server started on %s", lis.Addr())
+
+		go func() {
+			if err := s.grpc.Serve(lis); err != nil {
+				errs <- err
+			}
+		}()
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	select {
+	case err := <-errs:
+		s.shutdown()
+		return err
+	case sig := <-quit:
+		s.log.Infof("%s: shutting down", sig)
+	}
+
+	return s.shutdown()
+}
+
+// shutdown stops accepting connections and background jobs, and waits
+// for in-flight requests and jobs until server.shutdown_timeout passes.
+// Whatever is still running by then is cut off.
+func (s *Server) shutdown() error {
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		time.Second*time.Duration(s.cfg.Server.ShutdownTimeout),
+	)
+	defer cancel()
+
+	close(s.stop)
+
+	var wg sync.WaitGroup
+
+	if s.grpc != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			stopped := make(chan struct{})
+			go func() {
+				s.grpc.GracefulStop()
+				close(stopped)
+			}()
+
+			select {
+			case <-stopped:
+			case <-ctx.Done():
+				s.grpc.Stop()
+			}
+		}()
+	}
+
+	var err error
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		if err = s.router.Shutdown(ctx); err != nil {
+			s.router.Close()
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		done := make(chan struct{})
+		go func() {
+			s.jobs.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			s.log.Errorf("shutdown: background jobs still running")
+		}
+	}()
+
+	wg.Wait()
+
+	return err
 }
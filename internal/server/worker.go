@@ -0,0 +1,35 @@
+package server
+
+import "time"
+
+// schedule runs job every interval in the background until the server
+// shuts down. A non-positive interval disables the job.
+func (s *Server) schedule(
+	name string,
+	interval time.Duration,
+	job func() error,
+) {
+	if interval <= 0 {
+		s.log.Infof("%s: disabled", name)
+		return
+	}
+
+	s.jobs.Add(1)
+	go func() {
+		defer s.jobs.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				if err := job(); err != nil {
+					s.log.Errorf("%s: %v", name, err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,39 @@
+package uow
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	articleRepository "github.com/slavtov/clean-architecture/internal/article/repository"
+	attachmentRepository "github.com/slavtov/clean-architecture/internal/attachment/repository"
+	authRepository "github.com/slavtov/clean-architecture/internal/auth/repository"
+	commentRepository "github.com/slavtov/clean-architecture/internal/comment/repository"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	outboxRepository "github.com/slavtov/clean-architecture/internal/outbox/repository"
+	webhookRepository "github.com/slavtov/clean-architecture/internal/webhook/repository"
+	"github.com/slavtov/clean-architecture/pkg/store/postgres"
+)
+
+type unitOfWork struct {
+	db *sqlx.DB
+}
+
+func New(db *sqlx.DB) repositories.UnitOfWork {
+	return &unitOfWork{db}
+}
+
+func (u *unitOfWork) Do(
+	opts *sql.TxOptions,
+	fn func(r *repositories.TxRepositories) error,
+) error {
+	return postgres.WithTxOptions(u.db, opts, func(tx *sqlx.Tx) error {
+		return fn(&repositories.TxRepositories{
+			Users:       authRepository.NewPGRepository(tx),
+			Articles:    articleRepository.NewPGRepository(tx),
+			Comments:    commentRepository.NewPGRepository(tx),
+			Attachments: attachmentRepository.NewPGRepository(tx),
+			Outbox:      outboxRepository.NewPGRepository(tx),
+			Webhooks:    webhookRepository.NewPGRepository(tx),
+		})
+	})
+}
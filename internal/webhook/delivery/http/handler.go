@@ -0,0 +1,241 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/config"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/usecases"
+	"github.com/slavtov/clean-architecture/internal/middleware"
+	"github.com/slavtov/clean-architecture/pkg/logger"
+	"github.com/slavtov/clean-architecture/pkg/utils"
+)
+
+type handler struct {
+	webhookUseCase usecases.WebhookUseCase
+	log            logger.Logger
+}
+
+func newHandler(wu usecases.WebhookUseCase, log logger.Logger) *handler {
+	return &handler{
+		webhookUseCase: wu,
+		log:            log,
+	}
+}
+
+func Init(
+	cfg *config.Config,
+	e *echo.Group,
+	wu usecases.WebhookUseCase,
+	uu usecases.UserUseCase,
+	log logger.Logger,
+) {
+	h := newHandler(wu, log)
+	auth := middleware.Auth(cfg, uu, log)
+
+	e.GET("/webhooks", h.GetAll, auth)
+	e.POST("/webhooks", h.Store, auth)
+	e.GET("/webhooks/:id", h.GetByID, auth)
+	e.PUT("/webhooks/:id", h.Update, auth)
+	e.DELETE("/webhooks/:id", h.Delete, auth)
+	e.GET("/webhooks/:id/deliveries", h.GetDeliveries, auth)
+	e.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", h.Redeliver, auth)
+}
+
+// GetAll godoc
+// @Tags Webhooks
+// @Summary Get own webhooks
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.WebhooksList
+// @Failure 401,500 {object} swagger.Error
+// @Router /webhooks [get]
+func (h *handler) GetAll(c echo.Context) error {
+	res, err := h.webhookUseCase.GetAll(utils.GetCtxID(c))
+	if err != nil {
+		h.log.Errorf("webhook.UseCase.GetAll: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &models.WebhooksList{
+		TotalCount: len(res),
+		Webhooks:   res,
+	})
+}
+
+// GetByID godoc
+// @Tags Webhooks
+// @Summary Get webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Webhook
+// @Failure 401,404,500 {object} swagger.Error
+// @Router /webhooks/{id} [get]
+func (h *handler) GetByID(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	res, err := h.webhookUseCase.GetByID(id, utils.GetCtxID(c))
+	if err != nil {
+		h.log.Errorf("webhook.UseCase.GetByID: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// Store godoc
+// @Tags Webhooks
+// @Summary Create webhook
+// @Description Webhooks of users receive events about their own articles and account, webhooks of admins receive every event. Payloads are signed with HMAC-SHA256 of "<X-Webhook-Timestamp>.<body>" in X-Webhook-Signature. The signing secret is only returned here.
+// @Accept json
+// @Produce json
+// @Param body body swagger.WebhookRequest true "Body"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.Webhook
+// @Failure 400,401,500 {object} swagger.Error
+// @Router /webhooks [post]
+func (h *handler) Store(c echo.Context) error {
+	w := new(models.Webhook)
+
+	if err := c.Bind(w); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	w.UserID = utils.GetCtxID(c)
+
+	createdWebhook, err := h.webhookUseCase.Store(w)
+	if err != nil {
+		h.log.Errorf("webhook.UseCase.Store: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, createdWebhook)
+}
+
+// Update godoc
+// @Tags Webhooks
+// @Summary Update webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param body body swagger.WebhookRequest true "Body"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Webhook
+// @Failure 400,401,404,500 {object} swagger.Error
+// @Router /webhooks/{id} [put]
+func (h *handler) Update(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	w := new(models.Webhook)
+
+	if err := c.Bind(w); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	w.ID = id
+	w.UserID = utils.GetCtxID(c)
+
+	updatedWebhook, err := h.webhookUseCase.Update(w)
+	if err != nil {
+		h.log.Errorf("webhook.UseCase.Update: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, updatedWebhook)
+}
+
+// Delete godoc
+// @Tags Webhooks
+// @Summary Delete webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 401,404,500 {object} swagger.Error
+// @Router /webhooks/{id} [delete]
+func (h *handler) Delete(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	if err := h.webhookUseCase.Delete(id, utils.GetCtxID(c)); err != nil {
+		h.log.Errorf("webhook.UseCase.Delete: %v", err)
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetDeliveries godoc
+// @Tags Webhooks
+// @Summary Get webhook delivery log
+// @Description Latest deliveries first.
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.WebhookDeliveriesList
+// @Failure 401,404,500 {object} swagger.Error
+// @Router /webhooks/{id}/deliveries [get]
+func (h *handler) GetDeliveries(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	res, err := h.webhookUseCase.GetDeliveries(id, utils.GetCtxID(c))
+	if err != nil {
+		h.log.Errorf("webhook.UseCase.GetDeliveries: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &models.WebhookDeliveriesList{
+		TotalCount: len(res),
+		Deliveries: res,
+	})
+}
+
+// Redeliver godoc
+// @Tags Webhooks
+// @Summary Redeliver webhook delivery
+// @Description Sends a finished delivery again right away and returns its outcome. A failed redelivery is not retried. Pending deliveries are refused with 409.
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param delivery_id path string true "Delivery ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.WebhookDelivery
+// @Failure 401,404,409,500 {object} swagger.Error
+// @Router /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *handler) Redeliver(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("delivery_id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	res, err := h.webhookUseCase.Redeliver(deliveryID, id, utils.GetCtxID(c))
+	if err != nil {
+		h.log.Errorf("webhook.UseCase.Redeliver: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
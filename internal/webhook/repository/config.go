@@ -0,0 +1,53 @@
+package repository
+
+var (
+	getWebhooksQuery = `SELECT * FROM webhooks 
+								WHERE user_id = $1 ORDER BY created_at`
+	getWebhookQuery    = `SELECT * FROM webhooks WHERE id = $1`
+	countWebhooksQuery = `SELECT count(*) FROM webhooks WHERE user_id = $1`
+	createWebhookQuery = `INSERT INTO webhooks (user_id, url, events, secret) 
+								VALUES ($1, $2, $3, $4) RETURNING *`
+	updateWebhookQuery = `UPDATE webhooks 
+								SET url = $3, events = $4, active = $5, updated_at = now() 
+								WHERE id = $1 AND user_id = $2 RETURNING *`
+	deleteWebhookQuery     = `DELETE FROM webhooks WHERE id = $1 AND user_id = $2`
+	enqueueDeliveriesQuery = `INSERT INTO webhook_deliveries 
+								(webhook_id, event_id, event_type, payload) 
+								SELECT w.id, $1, $2, $3 FROM webhooks w 
+								JOIN users u ON u.id = w.user_id 
+								WHERE w.active AND $2 = ANY(w.events) 
+								AND u.deleted_at IS NULL 
+								AND (u.role = 'admin' OR w.user_id = $4) 
+								ON CONFLICT (webhook_id, event_id) DO NOTHING`
+	getDeliveriesQuery = `SELECT * FROM webhook_deliveries 
+								WHERE webhook_id = $1 
+								ORDER BY created_at DESC LIMIT $2`
+	getDeliveryQuery = `SELECT d.*, w.url, w.secret FROM webhook_deliveries d 
+								JOIN webhooks w ON w.id = d.webhook_id 
+								WHERE d.id = $1 AND d.webhook_id = $2`
+	leaseDeliveriesQuery = `WITH due AS (
+									SELECT d.id, w.url, w.secret FROM webhook_deliveries d 
+									JOIN webhooks w ON w.id = d.webhook_id 
+									WHERE d.status = 'pending' AND d.next_attempt_at <= now() 
+									AND w.active 
+									ORDER BY d.next_attempt_at LIMIT $1 
+									FOR UPDATE OF d SKIP LOCKED
+								) 
+								UPDATE webhook_deliveries d 
+								SET next_attempt_at = now() + make_interval(secs => $2) 
+								FROM due WHERE d.id = due.id 
+								RETURNING d.*, due.url, due.secret`
+	claimDeliveryQuery = `UPDATE webhook_deliveries d 
+								SET status = 'pending', next_attempt_at = now() + make_interval(secs => $3) 
+								FROM webhooks w 
+								WHERE w.id = d.webhook_id AND d.id = $1 AND d.webhook_id = $2 
+								AND d.status <> 'pending' 
+								RETURNING d.*, w.url, w.secret`
+	updateDeliveryQuery = `UPDATE webhook_deliveries 
+								SET status = $2, attempts = $3, next_attempt_at = $4, 
+								response_status = $5, response_body = $6, error = $7, 
+								duration_ms = $8, delivered_at = $9 
+								WHERE id = $1 RETURNING *`
+	purgeDeliveriesQuery = `DELETE FROM webhook_deliveries 
+								WHERE status <> 'pending' AND created_at < $1`
+)
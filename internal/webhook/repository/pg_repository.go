@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/pkg/store/postgres"
+)
+
+type pgRepository struct {
+	db postgres.DB
+}
+
+func NewPGRepository(db postgres.DB) repositories.PGWebhookRepository {
+	return &pgRepository{db}
+}
+
+func (r *pgRepository) GetAll(userID uuid.UUID) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+
+	if err := r.db.Select(
+		&webhooks,
+		getWebhooksQuery,
+		userID,
+	); err != nil {
+		return webhooks, echo.ErrInternalServerError
+	}
+
+	return webhooks, nil
+}
+
+func (r *pgRepository) GetByID(id uuid.UUID) (models.Webhook, error) {
+	var webhook models.Webhook
+
+	if err := r.db.Get(
+		&webhook,
+		getWebhookQuery,
+		id,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return webhook, echo.ErrNotFound
+		}
+
+		return webhook, echo.ErrBadRequest
+	}
+
+	return webhook, nil
+}
+
+func (r *pgRepository) Count(userID uuid.UUID) (int, error) {
+	var count int
+
+	if err := r.db.Get(&count, countWebhooksQuery, userID); err != nil {
+		return 0, echo.ErrInternalServerError
+	}
+
+	return count, nil
+}
+
+func (r *pgRepository) Store(w *models.Webhook) (*models.Webhook, error) {
+	var webhook models.Webhook
+
+	if err := r.db.QueryRowx(
+		createWebhookQuery,
+		w.UserID,
+		w.URL,
+		w.Events,
+		w.Secret,
+	).StructScan(&webhook); err != nil {
+		return nil, echo.ErrBadRequest
+	}
+
+	return &webhook, nil
+}
+
+func (r *pgRepository) Update(w *models.Webhook) (*models.Webhook, error) {
+	var webhook models.Webhook
+
+	if err := r.db.QueryRowx(
+		updateWebhookQuery,
+		w.ID,
+		w.UserID,
+		w.URL,
+		w.Events,
+		w.Active,
+	).StructScan(&webhook); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, echo.ErrNotFound
+		}
+
+		return nil, echo.ErrBadRequest
+	}
+
+	return &webhook, nil
+}
+
+func (r *pgRepository) Delete(id uuid.UUID, userID uuid.UUID) error {
+	res, err := r.db.Exec(deleteWebhookQuery, id, userID)
+	if err != nil {
+		return echo.ErrBadRequest
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	if rowsAffected == 0 {
+		return echo.ErrNotFound
+	}
+
+	return nil
+}
+
+// Enqueue creates a delivery of the event for every active webhook that
+// subscribes to it and belongs to the owner or an admin. Enqueueing the
+// same event twice is a no-op, so outbox redeliveries do not duplicate
+// webhook deliveries.
+func (r *pgRepository) Enqueue(
+	e *models.Event,
+	ownerID uuid.UUID,
+	body []byte,
+) (int64, error) {
+	res, err := r.db.Exec(
+		enqueueDeliveriesQuery,
+		e.ID,
+		e.Type,
+		body,
+		ownerID,
+	)
+	if err != nil {
+		return 0, echo.ErrInternalServerError
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, echo.ErrInternalServerError
+	}
+
+	return n, nil
+}
+
+func (r *pgRepository) GetDeliveries(
+	webhookID uuid.UUID,
+	limit int,
+) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+
+	if err := r.db.Select(
+		&deliveries,
+		getDeliveriesQuery,
+		webhookID,
+		limit,
+	); err != nil {
+		return deliveries, echo.ErrInternalServerError
+	}
+
+	return deliveries, nil
+}
+
+func (r *pgRepository) GetDelivery(
+	id uuid.UUID,
+	webhookID uuid.UUID,
+) (models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+
+	if err := r.db.Get(
+		&delivery,
+		getDeliveryQuery,
+		id,
+		webhookID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return delivery, echo.ErrNotFound
+		}
+
+		return delivery, echo.ErrBadRequest
+	}
+
+	return delivery, nil
+}
+
+// LeaseDeliveries claims the next pending deliveries that are due by
+// pushing their next attempt the lease duration ahead, so other workers
+// leave them alone without a lock being held while they are sent.
+// Deliveries the worker does not get to record in time become due again.
+func (r *pgRepository) LeaseDeliveries(
+	limit int,
+	lease time.Duration,
+) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+
+	if err := r.db.Select(
+		&deliveries,
+		leaseDeliveriesQuery,
+		limit,
+		lease.Seconds(),
+	); err != nil {
+		return deliveries, echo.ErrInternalServerError
+	}
+
+	return deliveries, nil
+}
+
+// ClaimDelivery leases a finished delivery for a redelivery, which puts it
+// back to pending until the outcome is recorded. A delivery that is
+// already pending belongs to a worker or another redelivery and cannot be
+// claimed.
+func (r *pgRepository) ClaimDelivery(
+	id uuid.UUID,
+	webhookID uuid.UUID,
+	lease time.Duration,
+) (models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+
+	if err := r.db.Get(
+		&delivery,
+		claimDeliveryQuery,
+		id,
+		webhookID,
+		lease.Seconds(),
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return delivery, echo.NewHTTPError(
+				http.StatusConflict,
+				"delivery is pending",
+			)
+		}
+
+		return delivery, echo.ErrInternalServerError
+	}
+
+	return delivery, nil
+}
+
+func (r *pgRepository) UpdateDelivery(
+	d *models.WebhookDelivery,
+) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+
+	if err := r.db.QueryRowx(
+		updateDeliveryQuery,
+		d.ID,
+		d.Status,
+		d.Attempts,
+		d.NextAttemptAt,
+		d.ResponseStatus,
+		d.ResponseBody,
+		d.Error,
+		d.DurationMs,
+		d.DeliveredAt,
+	).StructScan(&delivery); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, echo.ErrNotFound
+		}
+
+		return nil, echo.ErrInternalServerError
+	}
+
+	return &delivery, nil
+}
+
+func (r *pgRepository) PurgeDeliveries(before time.Time) (int64, error) {
+	res, err := r.db.Exec(purgeDeliveriesQuery, before)
+	if err != nil {
+		return 0, echo.ErrInternalServerError
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, echo.ErrInternalServerError
+	}
+
+	return n, nil
+}
@@ -0,0 +1,326 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/config"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/internal/domain/usecases"
+	"github.com/slavtov/clean-architecture/pkg/logger"
+	"github.com/slavtov/clean-architecture/pkg/utils"
+	"github.com/slavtov/clean-architecture/pkg/webhook"
+)
+
+type usecase struct {
+	cfg          *config.Config
+	pgRepository repositories.PGWebhookRepository
+	client       *webhook.Client
+	log          logger.Logger
+}
+
+const (
+	secretLength   = 16
+	maxBodyLength  = 1000
+	maxErrorLength = 1000
+)
+
+func New(
+	cfg *config.Config,
+	pg repositories.PGWebhookRepository,
+	log logger.Logger,
+) usecases.WebhookUseCase {
+	return &usecase{
+		cfg:          cfg,
+		pgRepository: pg,
+		client: webhook.New(
+			time.Second*time.Duration(cfg.Webhooks.Timeout),
+			cfg.Webhooks.AllowPrivate,
+		),
+		log: log,
+	}
+}
+
+func (u *usecase) GetAll(userID uuid.UUID) ([]models.Webhook, error) {
+	res, err := u.pgRepository.GetAll(userID)
+	if err != nil {
+		u.log.Errorf("webhook.pgRepository.GetAll: %v", err)
+		return res, err
+	}
+
+	for i := range res {
+		res[i].SanitizeSecret()
+	}
+
+	return res, nil
+}
+
+func (u *usecase) GetByID(
+	id uuid.UUID,
+	userID uuid.UUID,
+) (models.Webhook, error) {
+	res, err := u.pgRepository.GetByID(id)
+	if err != nil {
+		u.log.Errorf("webhook.pgRepository.GetByID: %v", err)
+		return res, err
+	}
+
+	if res.UserID != userID {
+		return models.Webhook{}, echo.ErrNotFound
+	}
+
+	res.SanitizeSecret()
+
+	return res, nil
+}
+
+// Store creates the webhook with a new signing secret. The response is
+// the only time the secret is shown.
+func (u *usecase) Store(w *models.Webhook) (*models.Webhook, error) {
+	if err := w.Validate(); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	count, err := u.pgRepository.Count(w.UserID)
+	if err != nil {
+		u.log.Errorf("webhook.pgRepository.Count: %v", err)
+		return nil, err
+	}
+
+	if count >= u.cfg.Webhooks.MaxPerUser {
+		return nil, echo.NewHTTPError(
+			http.StatusBadRequest,
+			"webhook limit reached",
+		)
+	}
+
+	secret := make([]byte, secretLength)
+	if _, err := rand.Read(secret); err != nil {
+		u.log.Errorf("webhook.rand.Read: %v", err)
+		return nil, echo.ErrInternalServerError
+	}
+	w.Secret = hex.EncodeToString(secret)
+
+	res, err := u.pgRepository.Store(w)
+	if err != nil {
+		u.log.Errorf("webhook.pgRepository.Store: %v", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (u *usecase) Update(w *models.Webhook) (*models.Webhook, error) {
+	if err := w.Validate(); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	res, err := u.pgRepository.Update(w)
+	if err != nil {
+		u.log.Errorf("webhook.pgRepository.Update: %v", err)
+		return nil, err
+	}
+
+	res.SanitizeSecret()
+
+	return res, nil
+}
+
+func (u *usecase) Delete(id uuid.UUID, userID uuid.UUID) error {
+	if err := u.pgRepository.Delete(id, userID); err != nil {
+		u.log.Errorf("webhook.pgRepository.Delete: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetDeliveries returns the delivery log of the webhook, latest first.
+func (u *usecase) GetDeliveries(
+	webhookID uuid.UUID,
+	userID uuid.UUID,
+) ([]models.WebhookDelivery, error) {
+	if _, err := u.GetByID(webhookID, userID); err != nil {
+		return nil, err
+	}
+
+	res, err := u.pgRepository.GetDeliveries(webhookID, u.cfg.Webhooks.LogSize)
+	if err != nil {
+		u.log.Errorf("webhook.pgRepository.GetDeliveries: %v", err)
+		return res, err
+	}
+
+	return res, nil
+}
+
+// Redeliver sends a finished delivery again right away. The outcome is
+// recorded but a failure is not retried automatically. A delivery that is
+// still pending is refused, it is due to be sent by a worker anyway.
+func (u *usecase) Redeliver(
+	id uuid.UUID,
+	webhookID uuid.UUID,
+	userID uuid.UUID,
+) (*models.WebhookDelivery, error) {
+	if _, err := u.GetByID(webhookID, userID); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.pgRepository.GetDelivery(id, webhookID); err != nil {
+		u.log.Errorf("webhook.pgRepository.GetDelivery: %v", err)
+		return nil, err
+	}
+
+	delivery, err := u.pgRepository.ClaimDelivery(id, webhookID, u.lease())
+	if err != nil {
+		u.log.Errorf("webhook.pgRepository.ClaimDelivery: %v", err)
+		return nil, err
+	}
+
+	if err := u.send(&delivery); err != nil {
+		u.log.Errorf("webhook.send: delivery %s: %v", delivery.ID, err)
+		delivery.Status = models.DeliveryFailed
+	}
+
+	res, err := u.pgRepository.UpdateDelivery(&delivery)
+	if err != nil {
+		u.log.Errorf("webhook.pgRepository.UpdateDelivery: %v", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Publish queues a delivery of the event for every webhook subscribed to
+// it. It is called by the outbox relay, which makes it an event sink.
+func (u *usecase) Publish(e *models.Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		u.log.Errorf("webhook.Marshal: %v", err)
+		return err
+	}
+
+	if _, err := u.pgRepository.Enqueue(e, e.OwnerID(), body); err != nil {
+		u.log.Errorf("webhook.pgRepository.Enqueue: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Deliver sends the pending deliveries that are due and returns how many
+// succeeded. Failed ones are retried with exponential backoff until they
+// run out of attempts. The batch is leased first and sent outside of any
+// transaction, so a slow receiver holds no row locks.
+func (u *usecase) Deliver(limit int) (int, error) {
+	deliveries, err := u.pgRepository.LeaseDeliveries(limit, u.lease())
+	if err != nil {
+		u.log.Errorf("webhook.pgRepository.LeaseDeliveries: %v", err)
+		return 0, err
+	}
+
+	succeeded := 0
+
+	for i := range deliveries {
+		d := &deliveries[i]
+
+		if err := u.send(d); err != nil {
+			u.log.Errorf("webhook.send: delivery %s: %v", d.ID, err)
+			u.retry(d)
+		} else {
+			succeeded++
+		}
+
+		if _, err := u.pgRepository.UpdateDelivery(d); err != nil {
+			u.log.Errorf("webhook.pgRepository.UpdateDelivery: %v", err)
+			return succeeded, err
+		}
+	}
+
+	return succeeded, nil
+}
+
+// Purge removes the finished deliveries created before the given time.
+func (u *usecase) Purge(before time.Time) (int64, error) {
+	n, err := u.pgRepository.PurgeDeliveries(before)
+	if err != nil {
+		u.log.Errorf("webhook.pgRepository.PurgeDeliveries: %v", err)
+		return 0, err
+	}
+
+	if n > 0 {
+		u.log.Infof("webhook.Purge: %d deliveries purged", n)
+	}
+
+	return n, nil
+}
+
+// send posts the delivery and records the attempt and the response on
+// it. The delivery is marked succeeded only for a 2xx response.
+func (u *usecase) send(d *models.WebhookDelivery) error {
+	res, err := u.client.Send(d.URL, d.Secret, map[string]string{
+		"X-Event-ID":    strconv.FormatInt(d.EventID, 10),
+		"X-Event-Type":  d.EventType,
+		"X-Webhook-ID":  d.WebhookID.String(),
+		"X-Delivery-ID": d.ID.String(),
+	}, d.Payload)
+
+	d.Attempts++
+	d.ResponseStatus = nil
+	d.ResponseBody = nil
+	d.Error = nil
+	d.DurationMs = nil
+
+	if res != nil {
+		body := utils.Truncate(sanitize(res.Body), maxBodyLength)
+		duration := int(res.Duration.Milliseconds())
+
+		d.ResponseStatus = &res.StatusCode
+		d.ResponseBody = &body
+		d.DurationMs = &duration
+	}
+
+	if err != nil {
+		reason := utils.Truncate(sanitize(err.Error()), maxErrorLength)
+		d.Error = &reason
+		return err
+	}
+
+	now := time.Now()
+	d.Status = models.DeliverySucceeded
+	d.DeliveredAt = &now
+
+	return nil
+}
+
+// retry schedules the next attempt of a failed delivery, or fails it for
+// good once it has run out of attempts.
+func (u *usecase) retry(d *models.WebhookDelivery) {
+	if d.Attempts >= u.cfg.Webhooks.MaxAttempts {
+		d.Status = models.DeliveryFailed
+		return
+	}
+
+	d.Status = models.DeliveryPending
+	d.NextAttemptAt = time.Now().Add(utils.Backoff(
+		time.Second*time.Duration(u.cfg.Webhooks.RetryBackoff),
+		time.Second*time.Duration(u.cfg.Webhooks.MaxRetryBackoff),
+		d.Attempts,
+	))
+}
+
+func (u *usecase) lease() time.Duration {
+	return time.Second * time.Duration(u.cfg.Webhooks.Lease)
+}
+
+// sanitize makes a response from an arbitrary receiver storable as text.
+func sanitize(s string) string {
+	return strings.ToValidUTF8(strings.ReplaceAll(s, "\x00", ""), "")
+}
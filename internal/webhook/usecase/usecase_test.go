@@ -0,0 +1,295 @@
+package usecase
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/slavtov/clean-architecture/internal/config"
+	"github.com/slavtov/clean-architecture/internal/domain/models"
+	"github.com/slavtov/clean-architecture/internal/domain/repositories"
+	"github.com/slavtov/clean-architecture/pkg/logger"
+	"github.com/slavtov/clean-architecture/pkg/webhook"
+)
+
+// fakeRepository keeps the deliveries of a single webhook in memory.
+// Methods the tests do not need panic through the nil interface.
+type fakeRepository struct {
+	repositories.PGWebhookRepository
+	webhook    models.Webhook
+	deliveries map[uuid.UUID]*models.WebhookDelivery
+}
+
+func newFakeRepository(url string) *fakeRepository {
+	return &fakeRepository{
+		webhook: models.Webhook{
+			ID:     uuid.New(),
+			UserID: uuid.New(),
+			URL:    url,
+			Secret: "secret",
+		},
+		deliveries: make(map[uuid.UUID]*models.WebhookDelivery),
+	}
+}
+
+func (r *fakeRepository) add(status string) *models.WebhookDelivery {
+	d := &models.WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     r.webhook.ID,
+		EventID:       1,
+		EventType:     models.EventArticleCreated,
+		Payload:       []byte(`{"id":1}`),
+		Status:        status,
+		NextAttemptAt: time.Now().Add(-time.Second),
+	}
+	r.deliveries[d.ID] = d
+
+	return d
+}
+
+func (r *fakeRepository) withWebhook(d models.WebhookDelivery) models.WebhookDelivery {
+	d.URL = r.webhook.URL
+	d.Secret = r.webhook.Secret
+	return d
+}
+
+func (r *fakeRepository) GetByID(id uuid.UUID) (models.Webhook, error) {
+	if id != r.webhook.ID {
+		return models.Webhook{}, echo.ErrNotFound
+	}
+
+	return r.webhook, nil
+}
+
+func (r *fakeRepository) GetDelivery(
+	id uuid.UUID,
+	webhookID uuid.UUID,
+) (models.WebhookDelivery, error) {
+	d, ok := r.deliveries[id]
+	if !ok || d.WebhookID != webhookID {
+		return models.WebhookDelivery{}, echo.ErrNotFound
+	}
+
+	return r.withWebhook(*d), nil
+}
+
+func (r *fakeRepository) LeaseDeliveries(
+	limit int,
+	lease time.Duration,
+) ([]models.WebhookDelivery, error) {
+	var res []models.WebhookDelivery
+
+	now := time.Now()
+	for _, d := range r.deliveries {
+		if len(res) == limit {
+			break
+		}
+
+		if d.Status == models.DeliveryPending && !d.NextAttemptAt.After(now) {
+			d.NextAttemptAt = now.Add(lease)
+			res = append(res, r.withWebhook(*d))
+		}
+	}
+
+	return res, nil
+}
+
+func (r *fakeRepository) ClaimDelivery(
+	id uuid.UUID,
+	webhookID uuid.UUID,
+	lease time.Duration,
+) (models.WebhookDelivery, error) {
+	d, ok := r.deliveries[id]
+	if !ok || d.WebhookID != webhookID || d.Status == models.DeliveryPending {
+		return models.WebhookDelivery{}, echo.NewHTTPError(
+			http.StatusConflict,
+			"delivery is pending",
+		)
+	}
+
+	d.Status = models.DeliveryPending
+	d.NextAttemptAt = time.Now().Add(lease)
+
+	return r.withWebhook(*d), nil
+}
+
+func (r *fakeRepository) UpdateDelivery(
+	d *models.WebhookDelivery,
+) (*models.WebhookDelivery, error) {
+	stored := *d
+	stored.URL = ""
+	stored.Secret = ""
+	r.deliveries[d.ID] = &stored
+
+	return &stored, nil
+}
+
+func newTestUseCase(repo *fakeRepository) *usecase {
+	cfg := &config.Config{Webhooks: config.WebhooksConfig{
+		BatchSize:       10,
+		Lease:           60,
+		MaxAttempts:     3,
+		RetryBackoff:    60,
+		MaxRetryBackoff: 100,
+		Timeout:         1,
+		AllowPrivate:    true,
+	}}
+
+	return New(cfg, repo, logger.New()).(*usecase)
+}
+
+// receiver answers with the given statuses in turn and counts the
+// requests that carry a valid signature.
+func receiver(t *testing.T, statuses ...int) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var calls int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+
+		if r.Header.Get(webhook.SignatureHeader) == "" {
+			t.Error("delivery is not signed")
+		}
+
+		w.WriteHeader(statuses[int(n-1)%len(statuses)])
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &calls
+}
+
+func TestDeliverSucceeds(t *testing.T) {
+	srv, calls := receiver(t, http.StatusOK)
+	repo := newFakeRepository(srv.URL)
+	d := repo.add(models.DeliveryPending)
+
+	n, err := newTestUseCase(repo).Deliver(10)
+	if err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+
+	if n != 1 || *calls != 1 {
+		t.Fatalf("Deliver = %d with %d requests, want 1", n, *calls)
+	}
+
+	got := repo.deliveries[d.ID]
+	if got.Status != models.DeliverySucceeded || got.Attempts != 1 ||
+		got.DeliveredAt == nil || got.Error != nil {
+		t.Fatalf("delivery = %+v, want succeeded after 1 attempt", got)
+	}
+
+	if got.ResponseStatus == nil || *got.ResponseStatus != http.StatusOK {
+		t.Fatalf("response status = %v, want 200", got.ResponseStatus)
+	}
+}
+
+func TestDeliverRetriesWithBackoff(t *testing.T) {
+	srv, calls := receiver(t, http.StatusInternalServerError)
+	repo := newFakeRepository(srv.URL)
+	d := repo.add(models.DeliveryPending)
+	u := newTestUseCase(repo)
+
+	// 60s after the first failure, then doubled but capped at 100s.
+	backoffs := []time.Duration{time.Second * 60, time.Second * 100}
+
+	for i, backoff := range backoffs {
+		before := time.Now()
+
+		n, err := u.Deliver(10)
+		if err != nil {
+			t.Fatalf("Deliver: %v", err)
+		}
+
+		got := repo.deliveries[d.ID]
+		if n != 0 || got.Status != models.DeliveryPending || got.Attempts != i+1 {
+			t.Fatalf("attempt %d: delivery = %+v, want pending", i+1, got)
+		}
+
+		if got.Error == nil || got.ResponseStatus == nil ||
+			*got.ResponseStatus != http.StatusInternalServerError {
+			t.Fatalf("attempt %d: the failure is not recorded: %+v", i+1, got)
+		}
+
+		if wait := got.NextAttemptAt.Sub(before); wait < backoff || wait > backoff+time.Second {
+			t.Fatalf("attempt %d: next attempt in %v, want %v", i+1, wait, backoff)
+		}
+
+		// A delivery that is not due yet is left alone.
+		if n, _ := u.Deliver(10); n != 0 || int(*calls) != i+1 {
+			t.Fatalf("attempt %d: a delivery that is not due was sent", i+1)
+		}
+
+		got.NextAttemptAt = time.Now().Add(-time.Second)
+	}
+
+	if _, err := u.Deliver(10); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+
+	got := repo.deliveries[d.ID]
+	if got.Status != models.DeliveryFailed || got.Attempts != 3 {
+		t.Fatalf("delivery = %+v, want failed after 3 attempts", got)
+	}
+
+	if n, _ := u.Deliver(10); n != 0 || *calls != 3 {
+		t.Fatal("a failed delivery was retried")
+	}
+}
+
+func TestRedeliver(t *testing.T) {
+	srv, calls := receiver(t, http.StatusOK, http.StatusInternalServerError)
+	repo := newFakeRepository(srv.URL)
+	d := repo.add(models.DeliveryFailed)
+	d.Attempts = 3
+	u := newTestUseCase(repo)
+
+	res, err := u.Redeliver(d.ID, repo.webhook.ID, repo.webhook.UserID)
+	if err != nil {
+		t.Fatalf("Redeliver: %v", err)
+	}
+
+	if res.Status != models.DeliverySucceeded || res.Attempts != 4 {
+		t.Fatalf("Redeliver = %+v, want succeeded after 4 attempts", res)
+	}
+
+	// A failed redelivery is recorded as failed, not scheduled again.
+	res, err = u.Redeliver(d.ID, repo.webhook.ID, repo.webhook.UserID)
+	if err != nil {
+		t.Fatalf("Redeliver: %v", err)
+	}
+
+	if res.Status != models.DeliveryFailed || res.Attempts != 5 || *calls != 2 {
+		t.Fatalf("Redeliver = %+v, want failed after 5 attempts", res)
+	}
+}
+
+func TestRedeliverRefusesPending(t *testing.T) {
+	srv, calls := receiver(t, http.StatusOK)
+	repo := newFakeRepository(srv.URL)
+	d := repo.add(models.DeliveryPending)
+
+	_, err := newTestUseCase(repo).Redeliver(d.ID, repo.webhook.ID, repo.webhook.UserID)
+	if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusConflict {
+		t.Fatalf("Redeliver = %v, want 409", err)
+	}
+
+	if *calls != 0 || repo.deliveries[d.ID].Attempts != 0 {
+		t.Fatal("a pending delivery was redelivered")
+	}
+}
+
+func TestRedeliverOfAnotherUsersWebhook(t *testing.T) {
+	srv, _ := receiver(t, http.StatusOK)
+	repo := newFakeRepository(srv.URL)
+	d := repo.add(models.DeliveryFailed)
+
+	_, err := newTestUseCase(repo).Redeliver(d.ID, repo.webhook.ID, uuid.New())
+	if err != echo.ErrNotFound {
+		t.Fatalf("Redeliver = %v, want 404", err)
+	}
+}
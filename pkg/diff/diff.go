@@ -0,0 +1,129 @@
+package diff
+
+import "strings"
+
+type Operation string
+
+const (
+	Equal  Operation = "equal"
+	Insert Operation = "insert"
+	Delete Operation = "delete"
+)
+
+type Op struct {
+	Type Operation `json:"type" example:"equal"`
+	Text string    `json:"text" example:"Text"`
+}
+
+// Lines compares a and b line by line.
+func Lines(a, b string) []Op {
+	return compute(splitLines(a), splitLines(b), "\n")
+}
+
+// Words compares a and b word by word.
+func Words(a, b string) []Op {
+	return compute(strings.Fields(a), strings.Fields(b), " ")
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(s, "\n")
+}
+
+// maxCells bounds the size of the LCS table, and so the memory and time a
+// single diff may take. Inputs whose changed middle exceeds it are
+// reported as one deletion followed by one insertion.
+const maxCells = 1 << 20
+
+// compute builds the shortest edit script from the longest common
+// subsequence of a and b and merges adjacent operations of the same type.
+// The common prefix and suffix are matched up front, so the table only
+// covers the part that changed.
+func compute(a, b []string, sep string) []Op {
+	ops := make([]Op, 0)
+	add := func(t Operation, text string) {
+		if n := len(ops); n > 0 && ops[n-1].Type == t {
+			ops[n-1].Text += sep + text
+			return
+		}
+
+		ops = append(ops, Op{Type: t, Text: text})
+	}
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		add(Equal, a[prefix])
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	middle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], add)
+
+	for _, s := range a[len(a)-suffix:] {
+		add(Equal, s)
+	}
+
+	return ops
+}
+
+func middle(a, b []string, add func(Operation, string)) {
+	if len(a) == 0 || len(b) == 0 || len(a)*len(b) > maxCells {
+		for _, s := range a {
+			add(Delete, s)
+		}
+
+		for _, s := range b {
+			add(Insert, s)
+		}
+
+		return
+	}
+
+	// lcs[i*w+j] is the length of the LCS of a[i:] and b[j:].
+	w := len(b) + 1
+	lcs := make([]int32, (len(a)+1)*w)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*w+j] = lcs[(i+1)*w+j+1] + 1
+			} else if lcs[(i+1)*w+j] >= lcs[i*w+j+1] {
+				lcs[i*w+j] = lcs[(i+1)*w+j]
+			} else {
+				lcs[i*w+j] = lcs[i*w+j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			add(Equal, a[i])
+			i++
+			j++
+		case lcs[(i+1)*w+j] >= lcs[i*w+j+1]:
+			add(Delete, a[i])
+			i++
+		default:
+			add(Insert, b[j])
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		add(Delete, a[i])
+	}
+
+	for ; j < len(b); j++ {
+		add(Insert, b[j])
+	}
+}
@@ -0,0 +1,85 @@
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/slavtov/clean-architecture/pkg/logger"
+)
+
+type Mailer interface {
+	Send(to string, subject string, body string) error
+}
+
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+type smtpMailer struct {
+	cfg *Config
+}
+
+type logMailer struct {
+	log logger.Logger
+}
+
+var errHeader = errors.New("mailer: invalid header value")
+
+// NewSMTP returns a Mailer that delivers plain text messages through an
+// SMTP relay.
+func NewSMTP(cfg *Config) Mailer {
+	return &smtpMailer{cfg}
+}
+
+// NewLog returns a Mailer that only writes messages to the log, for local
+// development.
+func NewLog(log logger.Logger) Mailer {
+	return &logMailer{log}
+}
+
+func (m *smtpMailer) Send(to string, subject string, body string) error {
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return errHeader
+	}
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	msg := fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\n"+
+			"MIME-Version: 1.0\r\n"+
+			"Content-Type: text/plain; charset=UTF-8\r\n\r\n%s",
+		m.cfg.From,
+		to,
+		subject,
+		body,
+	)
+
+	return smtp.SendMail(
+		net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port)),
+		auth,
+		m.cfg.From,
+		[]string{to},
+		[]byte(msg),
+	)
+}
+
+func (m *logMailer) Send(to string, subject string, body string) error {
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return errHeader
+	}
+
+	m.log.Infof("mailer: to=%s subject=%q\n%s", to, subject, body)
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+)
+
+var (
+	md = goldmark.New(
+		goldmark.WithExtensions(
+			extension.Table,
+			extension.Strikethrough,
+			extension.Linkify,
+		),
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+		),
+	)
+
+	policy = newPolicy()
+
+	paragraphs = regexp.MustCompile(`\n\s*\n`)
+)
+
+// newPolicy allows what user generated content usually needs, plus the
+// heading ids used as anchors and the language classes of code blocks.
+// Raw HTML is never rendered by the parser, so everything else that gets
+// here comes from markdown itself.
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+
+	p.AllowAttrs("id").
+		Matching(regexp.MustCompile(`^[a-z0-9_-]+$`)).
+		OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("class").
+		Matching(regexp.MustCompile(`^language-[\w+#.-]+$`)).
+		OnElements("code")
+
+	return p
+}
+
+// Render converts markdown to sanitized HTML.
+func Render(src string) (string, error) {
+	var buf bytes.Buffer
+
+	if err := md.Convert([]byte(src), &buf); err != nil {
+		return "", err
+	}
+
+	return policy.Sanitize(buf.String()), nil
+}
+
+// RenderPlain escapes plain text and splits it into paragraphs on blank
+// lines, keeping single line breaks.
+func RenderPlain(src string) string {
+	src = strings.ReplaceAll(strings.TrimSpace(src), "\r\n", "\n")
+	if src == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, p := range paragraphs.Split(src, -1) {
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(p), "\n", "<br>\n"))
+		b.WriteString("</p>\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,72 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// prefixLen is the length of the SHA-1 prefix that names a range file,
+// the same split the Pwned Passwords range API uses.
+const prefixLen = 5
+
+type BreachChecker interface {
+	IsBreached(password string) (bool, error)
+}
+
+type fileChecker struct {
+	dir string
+}
+
+type noopChecker struct{}
+
+// NewBreachChecker checks passwords against a local copy of a breached
+// password corpus: a directory of files named after the first five hex
+// characters of the SHA-1 hash (e.g. 21BD1.txt), each holding the rest of
+// the hashes as SUFFIX:COUNT lines. Only the file of the matching prefix is
+// read, the password itself never leaves the process. An empty dir turns
+// the check off.
+func NewBreachChecker(dir string) BreachChecker {
+	if dir == "" {
+		return noopChecker{}
+	}
+
+	return &fileChecker{dir}
+}
+
+func (c *fileChecker) IsBreached(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:prefixLen], hash[prefixLen:]
+
+	f, err := os.Open(filepath.Join(c.dir, prefix+".txt"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			line = line[:i]
+		}
+
+		if strings.EqualFold(strings.TrimSpace(line), suffix) {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+func (noopChecker) IsBreached(string) (bool, error) {
+	return false, nil
+}
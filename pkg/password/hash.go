@@ -0,0 +1,231 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	Argon2id = "argon2id"
+	Bcrypt   = "bcrypt"
+
+	// BcryptMaxLen is the number of bytes bcrypt looks at, anything after
+	// it would be silently ignored.
+	BcryptMaxLen = 72
+
+	saltLen = 16
+	keyLen  = 32
+
+	// Upper bounds for the argon2 parameters, so that a hash can not make
+	// a comparison take unbounded memory or time.
+	maxMemory      = 4 << 20 // KiB
+	maxIterations  = 64
+	maxParallelism = 64
+	maxKeyLen      = 128
+)
+
+var (
+	ErrMismatch = errors.New("password: hash and password do not match")
+	ErrTooLong  = fmt.Errorf("password: must be at most %d bytes for bcrypt", BcryptMaxLen)
+	ErrHash     = errors.New("password: unknown hash format")
+	ErrConfig   = errors.New("password: invalid hasher config")
+)
+
+type Config struct {
+	Algorithm   string
+	BcryptCost  int
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// Hasher hashes passwords with the configured algorithm and verifies
+// hashes made with any supported one, so that old hashes keep working
+// until they are upgraded.
+type Hasher struct {
+	cfg *Config
+}
+
+// NewHasher checks the config up front, as argon2 panics on zero
+// iterations or parallelism.
+func NewHasher(cfg *Config) (*Hasher, error) {
+	switch cfg.Algorithm {
+	case Bcrypt:
+		if cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf(
+				"%w: bcrypt cost must be between %d and %d",
+				ErrConfig,
+				bcrypt.MinCost,
+				bcrypt.MaxCost,
+			)
+		}
+	case Argon2id:
+		if !validArgon2(cfg) {
+			return nil, fmt.Errorf(
+				"%w: argon2 memory, iterations and parallelism must be "+
+					"positive and at most %d, %d and %d",
+				ErrConfig,
+				maxMemory,
+				maxIterations,
+				maxParallelism,
+			)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown algorithm %q", ErrConfig, cfg.Algorithm)
+	}
+
+	return &Hasher{cfg}, nil
+}
+
+func (h *Hasher) Hash(password string) (string, error) {
+	if h.cfg.Algorithm == Bcrypt {
+		if len(password) > BcryptMaxLen {
+			return "", ErrTooLong
+		}
+
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cfg.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+
+		return string(hash), nil
+	}
+
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey(
+		[]byte(password),
+		salt,
+		h.cfg.Iterations,
+		h.cfg.Memory,
+		h.cfg.Parallelism,
+		keyLen,
+	)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.cfg.Memory,
+		h.cfg.Iterations,
+		h.cfg.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *Hasher) Compare(hash string, password string) error {
+	if isBcrypt(hash) {
+		if err := bcrypt.CompareHashAndPassword(
+			[]byte(hash),
+			[]byte(password),
+		); err != nil {
+			return ErrMismatch
+		}
+
+		return nil
+	}
+
+	params, salt, key, err := decodeArgon2(hash)
+	if err != nil {
+		return err
+	}
+
+	other := argon2.IDKey(
+		[]byte(password),
+		salt,
+		params.Iterations,
+		params.Memory,
+		params.Parallelism,
+		uint32(len(key)),
+	)
+
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return ErrMismatch
+	}
+
+	return nil
+}
+
+// NeedsRehash reports whether the hash was made with another algorithm or
+// weaker parameters than the configured ones.
+func (h *Hasher) NeedsRehash(hash string) bool {
+	if h.cfg.Algorithm == Bcrypt {
+		if !isBcrypt(hash) {
+			return true
+		}
+
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || cost < h.cfg.BcryptCost
+	}
+
+	params, _, _, err := decodeArgon2(hash)
+	if err != nil {
+		return true
+	}
+
+	return params.Memory < h.cfg.Memory ||
+		params.Iterations < h.cfg.Iterations ||
+		params.Parallelism < h.cfg.Parallelism
+}
+
+func isBcrypt(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") ||
+		strings.HasPrefix(hash, "$2b$") ||
+		strings.HasPrefix(hash, "$2y$")
+}
+
+func decodeArgon2(hash string) (*Config, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != Argon2id {
+		return nil, nil, nil, ErrHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil ||
+		version != argon2.Version {
+		return nil, nil, nil, ErrHash
+	}
+
+	params := new(Config)
+	if _, err := fmt.Sscanf(
+		parts[3],
+		"m=%d,t=%d,p=%d",
+		&params.Memory,
+		&params.Iterations,
+		&params.Parallelism,
+	); err != nil {
+		return nil, nil, nil, ErrHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, ErrHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 || len(key) > maxKeyLen {
+		return nil, nil, nil, ErrHash
+	}
+
+	if !validArgon2(params) {
+		return nil, nil, nil, ErrHash
+	}
+
+	return params, salt, key, nil
+}
+
+func validArgon2(cfg *Config) bool {
+	return cfg.Memory > 0 && cfg.Memory <= maxMemory &&
+		cfg.Iterations > 0 && cfg.Iterations <= maxIterations &&
+		cfg.Parallelism > 0 && cfg.Parallelism <= maxParallelism
+}
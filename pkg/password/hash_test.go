@@ -0,0 +1,155 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testArgon2 keeps the tests fast; the parameters only have to be valid.
+var testArgon2 = &Config{
+	Algorithm:   Argon2id,
+	Memory:      64,
+	Iterations:  1,
+	Parallelism: 1,
+}
+
+func newTestHasher(t *testing.T, cfg *Config) *Hasher {
+	t.Helper()
+
+	h, err := NewHasher(cfg)
+	if err != nil {
+		t.Fatalf("NewHasher: %v", err)
+	}
+
+	return h
+}
+
+func TestNewHasherRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"empty", Config{}},
+		{"unknown algorithm", Config{Algorithm: "md5"}},
+		{"zero iterations", Config{Algorithm: Argon2id, Memory: 64, Parallelism: 1}},
+		{"zero parallelism", Config{Algorithm: Argon2id, Memory: 64, Iterations: 1}},
+		{"zero memory", Config{Algorithm: Argon2id, Iterations: 1, Parallelism: 1}},
+		{"huge memory", Config{Algorithm: Argon2id, Memory: maxMemory + 1, Iterations: 1, Parallelism: 1}},
+		{"bcrypt cost too low", Config{Algorithm: Bcrypt, BcryptCost: 1}},
+		{"bcrypt cost too high", Config{Algorithm: Bcrypt, BcryptCost: 32}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewHasher(&tt.cfg); !errors.Is(err, ErrConfig) {
+				t.Fatalf("NewHasher(%+v) = %v, want ErrConfig", tt.cfg, err)
+			}
+		})
+	}
+}
+
+func TestHashAndCompare(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+	}{
+		{"argon2id", testArgon2},
+		{"bcrypt", &Config{Algorithm: Bcrypt, BcryptCost: 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHasher(t, tt.cfg)
+
+			hash, err := h.Hash("correct horse")
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+
+			if err := h.Compare(hash, "correct horse"); err != nil {
+				t.Fatalf("Compare with the right password: %v", err)
+			}
+
+			if err := h.Compare(hash, "battery staple"); err != ErrMismatch {
+				t.Fatalf("Compare with a wrong password = %v, want ErrMismatch", err)
+			}
+
+			if h.NeedsRehash(hash) {
+				t.Fatal("NeedsRehash of a fresh hash")
+			}
+		})
+	}
+}
+
+func TestBcryptRejectsLongPasswords(t *testing.T) {
+	h := newTestHasher(t, &Config{Algorithm: Bcrypt, BcryptCost: 4})
+
+	if _, err := h.Hash(strings.Repeat("a", BcryptMaxLen+1)); err != ErrTooLong {
+		t.Fatalf("Hash = %v, want ErrTooLong", err)
+	}
+}
+
+func TestCompareAcrossAlgorithms(t *testing.T) {
+	bcryptHasher := newTestHasher(t, &Config{Algorithm: Bcrypt, BcryptCost: 4})
+	argon2Hasher := newTestHasher(t, testArgon2)
+
+	hash, err := bcryptHasher.Hash("secret")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+
+	if err := argon2Hasher.Compare(hash, "secret"); err != nil {
+		t.Fatalf("Compare of a bcrypt hash by an argon2id hasher: %v", err)
+	}
+
+	if !argon2Hasher.NeedsRehash(hash) {
+		t.Fatal("a bcrypt hash does not need a rehash to argon2id")
+	}
+}
+
+func TestNeedsRehashWithStrongerParams(t *testing.T) {
+	hash, err := newTestHasher(t, testArgon2).Hash("secret")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+
+	stronger := *testArgon2
+	stronger.Iterations++
+
+	if !newTestHasher(t, &stronger).NeedsRehash(hash) {
+		t.Fatal("NeedsRehash with more iterations = false")
+	}
+}
+
+func TestCompareRejectsBadHashes(t *testing.T) {
+	h := newTestHasher(t, testArgon2)
+
+	const salt, key = "c2FsdHNhbHRzYWx0c2FsdA", "a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2U"
+
+	// None of these may reach argon2, which panics on zero parameters.
+	hashes := map[string]string{
+		"empty":            "",
+		"garbage":          "not a hash",
+		"other version":    "$argon2id$v=16$m=64,t=1,p=1$" + salt + "$" + key,
+		"zero iterations":  "$argon2id$v=19$m=64,t=0,p=1$" + salt + "$" + key,
+		"zero parallelism": "$argon2id$v=19$m=64,t=1,p=0$" + salt + "$" + key,
+		"zero memory":      "$argon2id$v=19$m=0,t=1,p=1$" + salt + "$" + key,
+		"huge memory":      "$argon2id$v=19$m=4294967295,t=1,p=1$" + salt + "$" + key,
+		"huge iterations":  "$argon2id$v=19$m=64,t=4294967295,p=1$" + salt + "$" + key,
+		"empty key":        "$argon2id$v=19$m=64,t=1,p=1$" + salt + "$",
+		"bad salt":         "$argon2id$v=19$m=64,t=1,p=1$!!!$" + key,
+	}
+
+	for name, hash := range hashes {
+		t.Run(name, func(t *testing.T) {
+			if err := h.Compare(hash, "secret"); err != ErrHash {
+				t.Fatalf("Compare(%q) = %v, want ErrHash", hash, err)
+			}
+
+			if !h.NeedsRehash(hash) {
+				t.Fatalf("NeedsRehash(%q) = false", hash)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var ErrBreached = errors.New("password has appeared in a data breach, choose another one")
+
+type Policy struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+// Check reports the first rule of the policy the password breaks. The
+// email is the account's address, which is never a valid password.
+func (p *Policy) Check(password string, email string) error {
+	n := utf8.RuneCountInString(password)
+
+	if p.MinLength > 0 && n < p.MinLength {
+		return fmt.Errorf("password must be at least %d characters long", p.MinLength)
+	}
+
+	if p.MaxLength > 0 && n > p.MaxLength {
+		return fmt.Errorf("password must be at most %d characters long", p.MaxLength)
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	switch {
+	case p.RequireUpper && !upper:
+		return errors.New("password must contain an uppercase letter")
+	case p.RequireLower && !lower:
+		return errors.New("password must contain a lowercase letter")
+	case p.RequireDigit && !digit:
+		return errors.New("password must contain a digit")
+	case p.RequireSymbol && !symbol:
+		return errors.New("password must contain a symbol")
+	}
+
+	if email != "" {
+		local := email
+		if i := strings.LastIndex(email, "@"); i > 0 {
+			local = email[:i]
+		}
+
+		if strings.EqualFold(password, email) || strings.EqualFold(password, local) {
+			return errors.New("password must not be the email address")
+		}
+	}
+
+	return nil
+}
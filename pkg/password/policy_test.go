@@ -0,0 +1,37 @@
+package password
+
+import "testing"
+
+func TestPolicyCheck(t *testing.T) {
+	p := &Policy{
+		MinLength:     8,
+		MaxLength:     16,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+
+	tests := []struct {
+		password string
+		email    string
+		ok       bool
+	}{
+		{"Passw0rd!", "", true},
+		{"Pa0!", "", false},
+		{"Passw0rd!Passw0rd!", "", false},
+		{"passw0rd!", "", false},
+		{"PASSW0RD!", "", false},
+		{"Password!", "", false},
+		{"Passw0rdd", "", false},
+		{"Us3r!name", "us3r!name@example.com", false},
+		{"Us3r!name@example.com", "us3r!name@example.com", false},
+		{"Us3r!name", "other@example.com", true},
+	}
+
+	for _, tt := range tests {
+		if err := p.Check(tt.password, tt.email); (err == nil) != tt.ok {
+			t.Errorf("Check(%q, %q) = %v, want ok = %v", tt.password, tt.email, err, tt.ok)
+		}
+	}
+}
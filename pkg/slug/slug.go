@@ -0,0 +1,48 @@
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	maxLength = 100
+	fallback  = "article"
+)
+
+// Make turns s into a URL-friendly slug. Letters and digits of any script
+// are kept and lowercased, everything else collapses into single dashes.
+func Make(s string) string {
+	var b strings.Builder
+
+	n := 0
+	dash := false
+
+	for _, r := range norm.NFKC.String(s) {
+		if n >= maxLength {
+			break
+		}
+
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r):
+			if dash && b.Len() > 0 {
+				b.WriteRune('-')
+				n++
+			}
+
+			b.WriteRune(unicode.ToLower(r))
+			n++
+			dash = false
+		default:
+			dash = true
+		}
+	}
+
+	if b.Len() == 0 {
+		return fallback
+	}
+
+	return b.String()
+}
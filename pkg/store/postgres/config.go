@@ -1,6 +1,6 @@
 package postgres
 
-type config struct {
+type Config struct {
 	driver   string
 	host     string
 	port     int
@@ -18,7 +18,7 @@ func NewConfig(
 	password string,
 	name string,
 	ssl string,
-) *config {
+) *Config {
 	if host == "" {
 		host = "localhost"
 	}
@@ -31,7 +31,7 @@ func NewConfig(
 		ssl = "disable"
 	}
 
-	return &config{
+	return &Config{
 		driver:   driver,
 		host:     host,
 		port:     port,
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratePostgres "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// lockTimeout is how long a replica waits for another one to finish
+// migrating before giving up.
+const lockTimeout = time.Minute * 5
+
+var migrationPattern = regexp.MustCompile(`^(\d+)_(.+)\.up\.sql$`)
+
+type Migration struct {
+	Version uint
+	Name    string
+	Applied bool
+}
+
+// Migrator applies the migrations found in a file system. Every run holds
+// a Postgres advisory lock, so concurrent replicas migrate one at a time
+// and the ones that come later find nothing left to do.
+type Migrator struct {
+	db   *sql.DB
+	fsys fs.FS
+	path string
+	m    *migrate.Migrate
+}
+
+// NewMigrator opens its own connection, the migrate driver closes it when
+// done and must not take the application pool down with it.
+func NewMigrator(cfg *Config, fsys fs.FS, path string) (*Migrator, error) {
+	db, err := sql.Open(cfg.driver, cfg.dsn())
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := iofs.New(fsys, path)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	driver, err := migratePostgres.WithInstance(db, &migratePostgres.Config{})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, cfg.name, driver)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	m.LockTimeout = lockTimeout
+
+	return &Migrator{db, fsys, path, m}, nil
+}
+
+func (m *Migrator) Up() error {
+	return ignoreNoChange(m.m.Up())
+}
+
+// Down reverts the given number of migrations.
+func (m *Migrator) Down(steps int) error {
+	return ignoreNoChange(m.m.Steps(-steps))
+}
+
+func (m *Migrator) Goto(version uint) error {
+	return ignoreNoChange(m.m.Migrate(version))
+}
+
+// Force records version as applied and clears the dirty flag without
+// running anything. It is meant for recovering from a failed migration and
+// for baselining a database whose schema was created by other means.
+func (m *Migrator) Force(version uint) error {
+	return m.m.Force(int(version))
+}
+
+// Version returns the current version, 0 if nothing has been applied yet.
+func (m *Migrator) Version() (uint, bool, error) {
+	version, dirty, err := m.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+
+	return version, dirty, err
+}
+
+// Status lists the known migrations and whether they have been applied.
+func (m *Migrator) Status() ([]Migration, error) {
+	version, _, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := fs.ReadDir(m.fsys, m.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		match := migrationPattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		v, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, Migration{
+			Version: uint(v),
+			Name:    match[2],
+			Applied: uint(v) <= version,
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+	if srcErr != nil {
+		return srcErr
+	}
+
+	return dbErr
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,11 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+)
+
+// NullTime maps the zero time to SQL NULL.
+func NullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -7,8 +7,17 @@ import (
 	_ "github.com/lib/pq"
 )
 
-func NewClient(cfg *config) (*sqlx.DB, error) {
-	dsn := fmt.Sprintf(
+func NewClient(cfg *Config) (*sqlx.DB, error) {
+	db, err := sqlx.Connect(cfg.driver, cfg.dsn())
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func (cfg *Config) dsn() string {
+	return fmt.Sprintf(
 		`host=%s port=%d user=%s password=%s dbname=%s sslmode=%s`,
 		cfg.host,
 		cfg.port,
@@ -17,11 +26,4 @@ func NewClient(cfg *config) (*sqlx.DB, error) {
 		cfg.name,
 		cfg.ssl,
 	)
-
-	db, err := sqlx.Connect(cfg.driver, dsn)
-	if err != nil {
-		return nil, err
-	}
-
-	return db, nil
 }
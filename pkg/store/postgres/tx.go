@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DB is what repositories need to run queries. Both *sqlx.DB and *sqlx.Tx
+// implement it, so the same repository works inside a transaction.
+type DB interface {
+	sqlx.Ext
+	Get(dest interface{}, query string, args ...interface{}) error
+	Select(dest interface{}, query string, args ...interface{}) error
+}
+
+// WithTx runs fn inside a transaction. The transaction is rolled back
+// if fn returns an error or panics, and committed otherwise. When db is
+// already a transaction fn joins it, and the outer caller decides.
+func WithTx(db DB, fn func(tx *sqlx.Tx) error) error {
+	switch db := db.(type) {
+	case *sqlx.Tx:
+		return fn(db)
+	case *sqlx.DB:
+		return WithTxOptions(db, nil, fn)
+	}
+
+	return errors.New("postgres: unsupported DB")
+}
+
+// WithTxOptions is WithTx with an isolation level and read-only mode.
+func WithTxOptions(
+	db *sqlx.DB,
+	opts *sql.TxOptions,
+	fn func(tx *sqlx.Tx) error,
+) (err error) {
+	tx, err := db.BeginTxx(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	return fn(tx)
+}
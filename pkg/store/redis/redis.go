@@ -11,12 +11,31 @@ import (
 
 type Store interface {
 	Get(key string) (string, error)
+	GetDel(key string) (string, error)
+	MGet(keys ...string) ([]interface{}, error)
 	Set(key string, value interface{}, expiration time.Duration) error
+	SetMany(values map[string]interface{}, expiration time.Duration) error
 	Del(keys ...string) error
 	DelAll(pattern string) error
+	Keys(pattern string) ([]string, error)
+	TTL(key string) (time.Duration, error)
+	HIncrBy(key string, field string, incr int64) error
+	HMGet(key string, fields ...string) ([]interface{}, error)
+	HGetAll(key string) (map[string]string, error)
+	RenameNX(key string, newKey string) (bool, error)
+	XAdd(stream string, maxLen int64, values map[string]interface{}) (string, error)
+	XRange(stream string, start string, stop string, count int64) ([]StreamEntry, error)
+	XRevRange(stream string, stop string, start string, count int64) ([]StreamEntry, error)
+	Publish(channel string, message interface{}) error
+	Subscribe(ctx context.Context, channel string) (<-chan string, error)
 	store.Store
 }
 
+type StreamEntry struct {
+	ID     string
+	Values map[string]interface{}
+}
+
 type Config struct {
 	Addr     string
 	Password string
@@ -70,6 +89,38 @@ func (r *rdb) Get(key string) (string, error) {
 	return res, nil
 }
 
+// GetDel returns the value of key and deletes it in one transaction, so
+// that only one caller ever gets it.
+func (r *rdb) GetDel(key string) (string, error) {
+	var get *redis.StringCmd
+
+	if _, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, key)
+		pipe.Del(ctx, key)
+		return nil
+	}); err != nil && err != redis.Nil {
+		r.log.Errorf("redis.GetDel: %v", err)
+		return "", err
+	}
+
+	res, err := get.Result()
+	if err != nil {
+		return "", err
+	}
+
+	return res, nil
+}
+
+func (r *rdb) MGet(keys ...string) ([]interface{}, error) {
+	res, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		r.log.Errorf("redis.MGet: %v", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
 func (r *rdb) Set(
 	key string,
 	value interface{},
@@ -88,6 +139,29 @@ func (r *rdb) Set(
 	return nil
 }
 
+// SetMany sets every key of values in a single pipeline.
+func (r *rdb) SetMany(
+	values map[string]interface{},
+	expiration time.Duration,
+) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	if _, err := r.client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for key, value := range values {
+			p.Set(ctx, key, value, expiration)
+		}
+
+		return nil
+	}); err != nil {
+		r.log.Errorf("redis.SetMany: %v", err)
+		return err
+	}
+
+	return nil
+}
+
 func (r *rdb) Del(keys ...string) error {
 	if err := r.client.Del(ctx, keys...).Err(); err != nil {
 		r.log.Errorf("redis.Del: %v", err)
@@ -97,16 +171,218 @@ func (r *rdb) Del(keys ...string) error {
 	return nil
 }
 
+// DelAll deletes every key matching pattern. The keys are gone by the
+// time it returns, so a read right after it cannot see them.
 func (r *rdb) DelAll(pattern string) error {
+	keys, err := r.Keys(pattern)
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if err := r.client.Del(ctx, keys...).Err(); err != nil {
+		r.log.Errorf("redis.DelAll: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *rdb) Keys(pattern string) ([]string, error) {
+	var keys []string
+
 	iter := r.client.Scan(ctx, 0, pattern, 0).Iterator()
 	for iter.Next(ctx) {
-		go r.client.Del(ctx, iter.Val())
+		keys = append(keys, iter.Val())
 	}
 
 	if err := iter.Err(); err != nil {
-		r.log.Errorf("redis.DelAll: %v", err)
+		r.log.Errorf("redis.Keys: %v", err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *rdb) TTL(key string) (time.Duration, error) {
+	res, err := r.client.TTL(ctx, key).Result()
+	if err != nil {
+		r.log.Errorf("redis.TTL: %v", err)
+		return 0, err
+	}
+
+	return res, nil
+}
+
+func (r *rdb) HIncrBy(key string, field string, incr int64) error {
+	if err := r.client.HIncrBy(ctx, key, field, incr).Err(); err != nil {
+		r.log.Errorf("redis.HIncrBy: %v", err)
 		return err
 	}
 
 	return nil
 }
+
+func (r *rdb) HMGet(key string, fields ...string) ([]interface{}, error) {
+	res, err := r.client.HMGet(ctx, key, fields...).Result()
+	if err != nil {
+		r.log.Errorf("redis.HMGet: %v", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *rdb) HGetAll(key string) (map[string]string, error) {
+	res, err := r.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		r.log.Errorf("redis.HGetAll: %v", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// renameNX is RENAMENX that reports a missing key as not renamed instead
+// of failing, checked in the same atomic step.
+var renameNX = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+return redis.call("RENAMENX", KEYS[1], KEYS[2])
+`)
+
+// RenameNX renames key to newKey unless newKey already exists. A missing
+// key is not an error, it reports false like an existing newKey does.
+func (r *rdb) RenameNX(key string, newKey string) (bool, error) {
+	res, err := renameNX.Run(ctx, r.client, []string{key, newKey}).Int()
+	if err != nil {
+		r.log.Errorf("redis.RenameNX: %v", err)
+		return false, err
+	}
+
+	return res == 1, nil
+}
+
+// XAdd appends an entry to the stream and returns its ID. The stream is
+// trimmed to about maxLen entries; zero keeps every entry.
+func (r *rdb) XAdd(
+	stream string,
+	maxLen int64,
+	values map[string]interface{},
+) (string, error) {
+	res, err := r.client.XAdd(ctx, &redis.XAddArgs{
+		Stream:       stream,
+		MaxLenApprox: maxLen,
+		Values:       values,
+	}).Result()
+	if err != nil {
+		r.log.Errorf("redis.XAdd: %v", err)
+		return "", err
+	}
+
+	return res, nil
+}
+
+// XRange returns up to count entries of the stream between the IDs start
+// and stop, both inclusive. "-" and "+" stand for the first and last.
+func (r *rdb) XRange(
+	stream string,
+	start string,
+	stop string,
+	count int64,
+) ([]StreamEntry, error) {
+	res, err := r.client.XRangeN(ctx, stream, start, stop, count).Result()
+	if err != nil {
+		r.log.Errorf("redis.XRange: %v", err)
+		return nil, err
+	}
+
+	entries := make([]StreamEntry, 0, len(res))
+	for _, msg := range res {
+		entries = append(entries, StreamEntry{
+			ID:     msg.ID,
+			Values: msg.Values,
+		})
+	}
+
+	return entries, nil
+}
+
+// XRevRange is XRange in reverse order, from stop down to start.
+func (r *rdb) XRevRange(
+	stream string,
+	stop string,
+	start string,
+	count int64,
+) ([]StreamEntry, error) {
+	res, err := r.client.XRevRangeN(ctx, stream, stop, start, count).Result()
+	if err != nil {
+		r.log.Errorf("redis.XRevRange: %v", err)
+		return nil, err
+	}
+
+	entries := make([]StreamEntry, 0, len(res))
+	for _, msg := range res {
+		entries = append(entries, StreamEntry{
+			ID:     msg.ID,
+			Values: msg.Values,
+		})
+	}
+
+	return entries, nil
+}
+
+func (r *rdb) Publish(channel string, message interface{}) error {
+	if err := r.client.Publish(ctx, channel, message).Err(); err != nil {
+		r.log.Errorf("redis.Publish: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Subscribe returns the messages published to the channel until ctx is
+// done, when the channel is closed. The client reconnects on its own, but
+// messages published in the meantime are lost.
+func (r *rdb) Subscribe(
+	subCtx context.Context,
+	channel string,
+) (<-chan string, error) {
+	pubsub := r.client.Subscribe(subCtx, channel)
+	if _, err := pubsub.Receive(subCtx); err != nil {
+		pubsub.Close()
+		r.log.Errorf("redis.Subscribe: %v", err)
+		return nil, err
+	}
+
+	messages := make(chan string)
+
+	go func() {
+		defer close(messages)
+		defer pubsub.Close()
+
+		ch := pubsub.Channel()
+		for {
+			select {
+			case <-subCtx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+
+				select {
+				case messages <- msg.Payload:
+				case <-subCtx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return messages, nil
+}
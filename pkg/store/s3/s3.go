@@ -0,0 +1,142 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/slavtov/clean-architecture/pkg/logger"
+	"github.com/slavtov/clean-architecture/pkg/store"
+)
+
+type Store interface {
+	Put(key string, r io.Reader, size int64, contentType string) error
+	Get(key string) (io.ReadSeekCloser, error)
+	Del(key string) error
+	store.Store
+}
+
+type Config struct {
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	Region    string
+	SSL       bool
+}
+
+type s3 struct {
+	cfg    *Config
+	client *minio.Client
+	log    logger.Logger
+}
+
+// ErrNotFound is returned by Get for keys that do not exist.
+var ErrNotFound = errors.New("s3: key does not exist")
+
+var ctx = context.Background()
+
+func New(cfg *Config, log logger.Logger) Store {
+	return &s3{
+		cfg: cfg,
+		log: log,
+	}
+}
+
+// Open connects to any S3 compatible storage, e.g. AWS or MinIO, and
+// creates the bucket if it does not exist yet.
+func (s *s3) Open() error {
+	client, err := minio.New(s.cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(s.cfg.AccessKey, s.cfg.SecretKey, ""),
+		Secure: s.cfg.SSL,
+		Region: s.cfg.Region,
+	})
+	if err != nil {
+		return err
+	}
+
+	exists, err := client.BucketExists(ctx, s.cfg.Bucket)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if err := client.MakeBucket(ctx, s.cfg.Bucket, minio.MakeBucketOptions{
+			Region: s.cfg.Region,
+		}); err != nil {
+			return err
+		}
+	}
+
+	s.client = client
+
+	return nil
+}
+
+func (s *s3) Close() error {
+	return nil
+}
+
+func (s *s3) Put(
+	key string,
+	r io.Reader,
+	size int64,
+	contentType string,
+) error {
+	if _, err := s.client.PutObject(
+		ctx,
+		s.cfg.Bucket,
+		key,
+		r,
+		size,
+		minio.PutObjectOptions{ContentType: contentType},
+	); err != nil {
+		s.log.Errorf("s3.Put: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *s3) Get(key string) (io.ReadSeekCloser, error) {
+	obj, err := s.client.GetObject(
+		ctx,
+		s.cfg.Bucket,
+		key,
+		minio.GetObjectOptions{},
+	)
+	if err != nil {
+		s.log.Errorf("s3.Get: %v", err)
+		return nil, err
+	}
+
+	// GetObject is lazy, so a missing key only shows up on first access.
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrNotFound
+		}
+
+		s.log.Errorf("s3.Get: %v", err)
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+func (s *s3) Del(key string) error {
+	if err := s.client.RemoveObject(
+		ctx,
+		s.cfg.Bucket,
+		key,
+		minio.RemoveObjectOptions{},
+	); err != nil {
+		s.log.Errorf("s3.Del: %v", err)
+		return err
+	}
+
+	return nil
+}
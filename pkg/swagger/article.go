@@ -1,6 +1,33 @@
 package swagger
 
 type ArticleRequest struct {
-	Title string `json:"title" validate:"required" example:"Title"`
-	Desc  string `json:"desc" validate:"required" example:"Description"`
+	Title       string   `json:"title" validate:"required" example:"Title"`
+	Desc        string   `json:"desc" validate:"required" example:"Description"`
+	Format      string   `json:"format,omitempty" enums:"plain,markdown" example:"plain"`
+	Status      string   `json:"status,omitempty" enums:"draft,scheduled,published,archived" example:"draft"`
+	PublishedAt string   `json:"published_at,omitempty" example:"0000-01-01T00:00:00.000000Z"`
+	Tags        []string `json:"tags,omitempty" example:"golang"`
+}
+
+type ArticlesBulkCreateRequest struct {
+	Articles []ArticleRequest `json:"articles" validate:"required"`
+}
+
+type ArticleBulkUpdateItem struct {
+	ID          string   `json:"id" validate:"required" example:"00000000-0000-0000-0000-000000000000"`
+	UpdatedAt   string   `json:"updated_at,omitempty" example:"0000-01-01T00:00:00.000000Z"`
+	Title       string   `json:"title" validate:"required" example:"Title"`
+	Desc        string   `json:"desc" validate:"required" example:"Description"`
+	Format      string   `json:"format,omitempty" enums:"plain,markdown" example:"plain"`
+	Status      string   `json:"status,omitempty" enums:"draft,scheduled,published,archived" example:"draft"`
+	PublishedAt string   `json:"published_at,omitempty" example:"0000-01-01T00:00:00.000000Z"`
+	Tags        []string `json:"tags,omitempty" example:"golang"`
+}
+
+type ArticlesBulkUpdateRequest struct {
+	Articles []ArticleBulkUpdateItem `json:"articles" validate:"required"`
+}
+
+type ArticlesBulkDeleteRequest struct {
+	IDs []string `json:"ids" validate:"required" example:"00000000-0000-0000-0000-000000000000"`
 }
@@ -0,0 +1,10 @@
+package swagger
+
+type CommentRequest struct {
+	Body     string `json:"body" validate:"required" example:"Comment"`
+	ParentID string `json:"parent_id,omitempty" example:"00000000-0000-0000-0000-000000000000"`
+}
+
+type UpdateComment struct {
+	Body string `json:"body" validate:"required" example:"Comment"`
+}
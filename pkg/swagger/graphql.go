@@ -0,0 +1,12 @@
+package swagger
+
+type GraphQLRequest struct {
+	Query         string                 `json:"query" validate:"required" example:"{ articles { title author { handle } } }"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type GraphQLResponse struct {
+	Data   map[string]interface{}   `json:"data"`
+	Errors []map[string]interface{} `json:"errors"`
+}
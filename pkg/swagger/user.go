@@ -5,7 +5,21 @@ type UserRequest struct {
 	Password string `json:"password" validate:"required" example:"password"`
 }
 
+type RegisterUser struct {
+	Email       string `json:"email" validate:"required" example:"test@test.test"`
+	Password    string `json:"password" validate:"required" example:"password"`
+	Handle      string `json:"handle,omitempty" example:"test"`
+	DisplayName string `json:"display_name,omitempty" example:"Test"`
+	Bio         string `json:"bio,omitempty" example:"Bio"`
+	AvatarURL   string `json:"avatar_url,omitempty" example:"https://example.com/avatar.png"`
+}
+
 type UpdateUser struct {
-	Email    string `json:"email" validate:"required" example:"test@test.test"`
-	Password string `json:"password,omitempty" example:"password"`
+	Email           string `json:"email" validate:"required" example:"test@test.test"`
+	Password        string `json:"password,omitempty" example:"password"`
+	CurrentPassword string `json:"current_password,omitempty" example:"password"`
+	Handle          string `json:"handle,omitempty" example:"test"`
+	DisplayName     string `json:"display_name,omitempty" example:"Test"`
+	Bio             string `json:"bio,omitempty" example:"Bio"`
+	AvatarURL       string `json:"avatar_url,omitempty" example:"https://example.com/avatar.png"`
 }
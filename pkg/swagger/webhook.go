@@ -0,0 +1,7 @@
+package swagger
+
+type WebhookRequest struct {
+	URL    string   `json:"url" validate:"required" example:"https://example.com/webhook"`
+	Events []string `json:"events" validate:"required" enums:"article.created,article.updated,article.deleted,user.registered,user.deleted" example:"article.created"`
+	Active bool     `json:"active" example:"true"`
+}
@@ -0,0 +1,19 @@
+package utils
+
+import "time"
+
+// Backoff returns how long to wait before the next attempt after the
+// given number of failed attempts: base, doubled after every further
+// failure, and capped at max unless max is zero.
+func Backoff(base time.Duration, max time.Duration, attempts int) time.Duration {
+	backoff := base
+	for i := 1; i < attempts && (max == 0 || backoff < max); i++ {
+		backoff *= 2
+	}
+
+	if max > 0 && backoff > max {
+		backoff = max
+	}
+
+	return backoff
+}
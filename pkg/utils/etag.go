@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderETag        = "ETag"
+	HeaderIfMatch     = "If-Match"
+	HeaderIfNoneMatch = "If-None-Match"
+)
+
+var errInvalidETag = errors.New("invalid etag")
+
+// ETag derives an entity tag from the modification time of a resource.
+func ETag(updatedAt time.Time) string {
+	return fmt.Sprintf(`"%x"`, updatedAt.UnixMicro())
+}
+
+// HashETag derives an entity tag from the given parts, e.g. for a list
+// of resources.
+func HashETag(parts ...string) string {
+	return fmt.Sprintf(`"%x"`, sha1.Sum([]byte(strings.Join(parts, ";"))))
+}
+
+// VersionETag derives an entity tag from the modification time of a
+// resource and the given parts, for representations that also change
+// without being modified, e.g. with counters. ParseETag reads the
+// modification time back, so the tag still works with If-Match.
+func VersionETag(updatedAt time.Time, parts ...string) string {
+	return fmt.Sprintf(
+		`"%x-%x"`,
+		updatedAt.UnixMicro(),
+		sha1.Sum([]byte(strings.Join(parts, ";"))),
+	)
+}
+
+// ParseETag returns the modification time encoded in a strong entity tag.
+// Weak tags are rejected, as If-Match requires strong comparison.
+func ParseETag(etag string) (time.Time, error) {
+	etag = strings.TrimSpace(etag)
+	if len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		return time.Time{}, errInvalidETag
+	}
+
+	etag = etag[1 : len(etag)-1]
+	if i := strings.IndexByte(etag, '-'); i >= 0 {
+		etag = etag[:i]
+	}
+
+	usec, err := strconv.ParseInt(etag, 16, 64)
+	if err != nil {
+		return time.Time{}, errInvalidETag
+	}
+
+	return time.UnixMicro(usec), nil
+}
+
+// GetIfMatch returns the modification time the client expects the
+// resource to have, or the zero time when the request is unconditional.
+func GetIfMatch(c echo.Context) (time.Time, error) {
+	ifMatch := strings.TrimSpace(c.Request().Header.Get(HeaderIfMatch))
+	if ifMatch == "" || ifMatch == "*" {
+		return time.Time{}, nil
+	}
+
+	return ParseETag(ifMatch)
+}
+
+// NotModified sets the ETag and Last-Modified validators on the response
+// and reports whether the copy cached by the client is still fresh.
+// If-None-Match takes precedence over If-Modified-Since.
+func NotModified(c echo.Context, etag string, lastModified time.Time) bool {
+	req := c.Request()
+	header := c.Response().Header()
+
+	header.Set(HeaderETag, etag)
+	if !lastModified.IsZero() {
+		header.Set(
+			echo.HeaderLastModified,
+			lastModified.UTC().Format(http.TimeFormat),
+		)
+	}
+
+	if ifNoneMatch := req.Header.Get(HeaderIfNoneMatch); ifNoneMatch != "" {
+		for _, tag := range strings.Split(ifNoneMatch, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if lastModified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(req.Header.Get(echo.HeaderIfModifiedSince))
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.Truncate(time.Second).After(since)
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type grpcCtxKey struct{}
+
+type grpcAuth struct {
+	userID uuid.UUID
+	tokens TokenDetails
+}
+
+// SetGrpcAuth returns a copy of ctx carrying the authenticated user and
+// the IDs of the tokens they presented.
+func SetGrpcAuth(
+	ctx context.Context,
+	userID uuid.UUID,
+	td *TokenDetails,
+) context.Context {
+	return context.WithValue(ctx, grpcCtxKey{}, &grpcAuth{
+		userID: userID,
+		tokens: TokenDetails{AtID: td.AtID, RtID: td.RtID},
+	})
+}
+
+// GetGrpcViewerID returns the authenticated user, or uuid.Nil for
+// anonymous calls.
+func GetGrpcViewerID(ctx context.Context) uuid.UUID {
+	a, ok := ctx.Value(grpcCtxKey{}).(*grpcAuth)
+	if !ok {
+		return uuid.Nil
+	}
+
+	return a.userID
+}
+
+// GetGrpcTokens returns the token IDs of the authenticated call, or nil
+// for anonymous calls.
+func GetGrpcTokens(ctx context.Context) *TokenDetails {
+	a, ok := ctx.Value(grpcCtxKey{}).(*grpcAuth)
+	if !ok {
+		return nil
+	}
+
+	return &TokenDetails{AtID: a.tokens.AtID, RtID: a.tokens.RtID}
+}
@@ -9,6 +9,17 @@ func GetCtxID(c echo.Context) uuid.UUID {
 	return c.Get("user_id").(uuid.UUID)
 }
 
+// GetCtxViewerID returns the authenticated user, or uuid.Nil for
+// anonymous requests.
+func GetCtxViewerID(c echo.Context) uuid.UUID {
+	id, ok := c.Get("user_id").(uuid.UUID)
+	if !ok {
+		return uuid.Nil
+	}
+
+	return id
+}
+
 func GetCtxAccessID(c echo.Context) uuid.UUID {
 	return c.Get("access_id").(uuid.UUID)
 }
@@ -117,10 +117,28 @@ func ValidateToken(
 	tokenString string,
 	secret string,
 ) error {
+	tokenID, userID, err := ParseToken(tokenName, tokenString, secret)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fmt.Sprintf("%s_id", tokenName), tokenID)
+	c.Set("user_id", userID)
+
+	return nil
+}
+
+// ParseToken verifies the token and returns its ID and the ID of the
+// user it was issued to.
+func ParseToken(
+	tokenName string,
+	tokenString string,
+	secret string,
+) (uuid.UUID, uuid.UUID, error) {
 	errString := fmt.Sprintf("invalid %s token", tokenName)
 
 	if tokenString == "" {
-		return errors.New(errString)
+		return uuid.Nil, uuid.Nil, errors.New(errString)
 	}
 
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
@@ -131,37 +149,33 @@ func ValidateToken(
 		return []byte(secret), nil
 	})
 	if err != nil {
-		return err
+		return uuid.Nil, uuid.Nil, err
 	}
 
-	if !token.Valid {
-		return errors.New(errString)
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return uuid.Nil, uuid.Nil, errors.New(errString)
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		id, ok := claims["id"].(string)
-		if !ok {
-			return errors.New("invalid jwt claims")
-		}
-
-		userID, ok := claims["user_id"].(string)
-		if !ok {
-			return errors.New("invalid jwt claims")
-		}
+	id, ok := claims["id"].(string)
+	if !ok {
+		return uuid.Nil, uuid.Nil, errors.New("invalid jwt claims")
+	}
 
-		tokenUuid, err := uuid.Parse(id)
-		if err != nil {
-			return err
-		}
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return uuid.Nil, uuid.Nil, errors.New("invalid jwt claims")
+	}
 
-		userUuid, err := uuid.Parse(userID)
-		if err != nil {
-			return err
-		}
+	tokenUuid, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
 
-		c.Set(fmt.Sprintf("%s_id", tokenName), tokenUuid)
-		c.Set("user_id", userUuid)
+	userUuid, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
 	}
 
-	return nil
+	return tokenUuid, userUuid, nil
 }
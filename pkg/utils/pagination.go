@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetPagination reads the limit and offset query parameters. Missing ones
+// are returned as zero and left for the caller to default.
+func GetPagination(c echo.Context) (limit int, offset int, err error) {
+	if param := c.QueryParam("limit"); param != "" {
+		if limit, err = strconv.Atoi(param); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if param := c.QueryParam("offset"); param != "" {
+		if offset, err = strconv.Atoi(param); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return limit, offset, nil
+}
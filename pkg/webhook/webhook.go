@@ -0,0 +1,136 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+)
+
+type Client struct {
+	http *http.Client
+}
+
+// Response is what the receiver answered, kept for delivery logs.
+type Response struct {
+	StatusCode int
+	Body       string
+	Duration   time.Duration
+}
+
+const maxResponseBody = 1024
+
+var ErrPrivateAddress = errors.New("webhook: private address not allowed")
+
+// New returns a client whose requests give up after timeout. Unless
+// allowPrivate is set it refuses to connect to loopback, private and
+// link-local addresses, so user supplied URLs cannot reach internal
+// services. The check runs on the resolved address of every connection,
+// redirects included.
+func New(timeout time.Duration, allowPrivate bool) *Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			ip := net.ParseIP(host)
+			if ip == nil ||
+				ip.IsLoopback() ||
+				ip.IsPrivate() ||
+				ip.IsUnspecified() ||
+				ip.IsLinkLocalUnicast() ||
+				ip.IsLinkLocalMulticast() {
+				return ErrPrivateAddress
+			}
+
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// A proxy would make the connection, so the address check above would
+	// only ever see the proxy's address.
+	transport.Proxy = nil
+
+	return &Client{&http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}}
+}
+
+// Send posts the JSON body to url. With a secret the request is signed,
+// see Sign. Any response outside 2xx is an error.
+func (c *Client) Send(
+	url string,
+	secret string,
+	headers map[string]string,
+	body []byte,
+) (*Response, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	if secret != "" {
+		timestamp := time.Now().Unix()
+		req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(SignatureHeader, Sign(secret, timestamp, body))
+	}
+
+	start := time.Now()
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	data, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxResponseBody))
+
+	resp := &Response{
+		StatusCode: res.StatusCode,
+		Body:       string(data),
+		Duration:   time.Since(start),
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return resp, fmt.Errorf("webhook: unexpected status %d", res.StatusCode)
+	}
+
+	return resp, nil
+}
+
+// Sign returns the signature receivers check: the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the secret. Including the timestamp
+// lets them reject replayed requests.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
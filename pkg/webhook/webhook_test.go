@@ -0,0 +1,117 @@
+package webhook
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSendSignsTheRequest(t *testing.T) {
+	body := []byte(`{"id":1}`)
+
+	var got *http.Request
+	var gotBody []byte
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = ioutil.ReadAll(r.Body)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	res, err := New(time.Second, true).Send(
+		srv.URL,
+		"secret",
+		map[string]string{"X-Event-ID": "1"},
+		body,
+	)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK || res.Body != "ok" {
+		t.Fatalf("Send = %+v, want 200 ok", res)
+	}
+
+	if string(gotBody) != string(body) {
+		t.Fatalf("body = %q, want %q", gotBody, body)
+	}
+
+	if ct := got.Header.Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	if id := got.Header.Get("X-Event-ID"); id != "1" {
+		t.Fatalf("X-Event-ID = %q", id)
+	}
+
+	timestamp, err := strconv.ParseInt(got.Header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		t.Fatalf("%s: %v", TimestampHeader, err)
+	}
+
+	if sig := got.Header.Get(SignatureHeader); sig != Sign("secret", timestamp, body) {
+		t.Fatalf("%s = %q, want %q", SignatureHeader, sig, Sign("secret", timestamp, body))
+	}
+}
+
+func TestSendWithoutSecret(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(SignatureHeader) != "" || r.Header.Get(TimestampHeader) != "" {
+			t.Error("unsigned request carries a signature")
+		}
+	}))
+	defer srv.Close()
+
+	if _, err := New(time.Second, true).Send(srv.URL, "", nil, []byte("{}")); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+}
+
+func TestSendFailsOnNon2xx(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("down"))
+	}))
+	defer srv.Close()
+
+	res, err := New(time.Second, true).Send(srv.URL, "secret", nil, []byte("{}"))
+	if err == nil {
+		t.Fatal("Send succeeded on a 503")
+	}
+
+	// The response is still returned for the delivery log.
+	if res == nil || res.StatusCode != http.StatusServiceUnavailable || res.Body != "down" {
+		t.Fatalf("Send = %+v, want the 503 response", res)
+	}
+}
+
+func TestSendRefusesPrivateAddresses(t *testing.T) {
+	called := false
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	_, err := New(time.Second, false).Send(srv.URL, "secret", nil, []byte("{}"))
+	if !errors.Is(err, ErrPrivateAddress) {
+		t.Fatalf("Send to %s = %v, want ErrPrivateAddress", srv.URL, err)
+	}
+
+	if called {
+		t.Fatal("the request reached the server")
+	}
+}
+
+func TestNewIgnoresProxies(t *testing.T) {
+	transport := New(time.Second, false).http.Transport.(*http.Transport)
+
+	if transport.Proxy != nil {
+		t.Fatal("the client uses a proxy, which bypasses the address check")
+	}
+}